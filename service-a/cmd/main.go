@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,106 +11,146 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/marfebr/otel-lab/service-a/internal/auth"
+	"github.com/marfebr/otel-lab/service-a/internal/config"
+	"github.com/marfebr/otel-lab/service-a/internal/logging"
+	"github.com/marfebr/otel-lab/service-a/internal/telemetry"
 	"github.com/marfebr/otel-lab/service-a/internal/web"
-	"github.com/spf13/viper"
-
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 )
 
-func initProvider(serviceName, collectorURL string) (func(context.Context) error, error) {
-	ctx := context.Background()
-
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(serviceName),
-		),
-	)
+// replay reenvia ao collector os arquivos gravados pelo exportador "file"
+// Uso: <binário> replay [-endpoint host:porta] arquivo.jsonl...
+func replay(args []string) {
+	cfg, err := config.Load(nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create resource: %w", err)
+		log.Fatalf("invalid configuration:\n%v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, time.Second)
-	defer cancel()
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	endpoint := flags.String("endpoint", cfg.Telemetry.OTLPGRPCEndpoint, "OTLP gRPC endpoint of the collector")
+	timeout := flags.Duration("timeout", time.Minute, "timeout for the whole replay")
+	flags.Parse(args)
 
-	conn, err := grpc.NewClient(collectorURL,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create gRPC connection to collector: %w", err)
+	if flags.NArg() == 0 {
+		log.Fatal("usage: replay [-endpoint host:port] [-timeout 1m] file.jsonl...")
 	}
 
-	traceExporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	stats, err := telemetry.Replay(ctx, *endpoint, cfg.Telemetry.OTLPTLS, flags.Args())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+		log.Fatal(err)
 	}
-
-	bsp := sdktrace.NewBatchSpanProcessor(traceExporter)
-	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-		sdktrace.WithResource(res),
-		sdktrace.WithSpanProcessor(bsp),
-	)
-	otel.SetTracerProvider(tracerProvider)
-
-	otel.SetTextMapPropagator(propagation.TraceContext{})
-
-	return tracerProvider.Shutdown, nil
+	log.Printf("Replay to %s finished: %d trace batches, %d metric batches, %d lines skipped",
+		*endpoint, stats.TraceBatches, stats.MetricBatches, stats.Skipped)
 }
 
-// load env vars cfg
-func init() {
-	viper.AutomaticEnv()
-	// Configurar valores padrão
-	viper.SetDefault("SERVICE_B_URL", "http://service-b:8181")
-	viper.SetDefault("HTTP_PORT", ":8080")
-	viper.SetDefault("OTEL_SERVICE_NAME", "service-a")
-	viper.SetDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "otel-collector:4317")
+// hashKey imprime o hash SHA-256 de uma chave de API para uso em API_KEYS/API_KEYS_FILE
+// Uso: <binário> hash-key <chave>
+func hashKey(args []string) {
+	if len(args) != 1 {
+		log.Fatal("usage: hash-key <api-key>")
+	}
+	fmt.Println(auth.HashKey(args[0]))
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		replay(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "hash-key" {
+		hashKey(os.Args[2:])
+		return
+	}
+
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, config.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+	if err := logging.Setup(cfg.LogLevel); err != nil {
+		log.Fatal(err)
+	}
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	shutdown, err := initProvider(viper.GetString("OTEL_SERVICE_NAME"), viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"))
+	// Página /debug/traces alimentada por spans em memória (desligada por padrão)
+	var spanStore *telemetry.SpanStore
+	if cfg.DebugTraces.Enabled {
+		spanStore = telemetry.NewSpanStore(cfg.DebugTraces.MaxSpans)
+		cfg.Telemetry.Processors = append(cfg.Telemetry.Processors, spanStore)
+	}
+
+	shutdown, err := telemetry.InitProvider(ctx, cfg.Telemetry)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer func() {
-		if err := shutdown(ctx); err != nil {
-			log.Fatal("failed to shutdown TracerProvider: %w", err)
+		// Falhas ao descarregar spans (ex.: collector ausente) não devem derrubar o processo
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer flushCancel()
+		if err := shutdown(flushCtx); err != nil {
+			log.Printf("failed to shutdown TracerProvider: %v", err)
 		}
 	}()
 
 	tracer := otel.Tracer("service-a-tracer")
 
 	// Criar servidor web
-	serviceBURL := viper.GetString("SERVICE_B_URL")
-	server := web.NewServer(tracer, serviceBURL)
+	server, err := web.NewServer(tracer, cfg, spanStore)
+	if err != nil {
+		log.Fatal(err)
+	}
 	router := server.GetRouter()
 
+	// Recarregar as configurações seguras ao alterar o arquivo ou receber SIGHUP
+	watcher := config.NewWatcher(cfg, os.Args[1:])
+	watcher.OnReload(func(cfg *config.Config) {
+		if err := logging.SetLevel(cfg.LogLevel); err != nil {
+			log.Printf("failed to apply log level: %v", err)
+		}
+		if err := telemetry.UpdateSampler(cfg.Telemetry.Sampler.Name, cfg.Telemetry.Sampler.Arg); err != nil {
+			log.Printf("failed to apply sampler: %v", err)
+		}
+	})
+	watcher.OnReload(server.ApplyConfig)
+	if err := watcher.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+
 	// Configurar servidor HTTP
 	httpServer := &http.Server{
-		Addr:    viper.GetString("HTTP_PORT"),
+		Addr:    cfg.HTTPPort,
 		Handler: router,
 	}
+	// HTTPS quando há certificado configurado (recarregado ao mudar os arquivos)
+	httpServer.TLSConfig, err = web.ListenerTLSConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	log.Printf("Service A initialized with OTEL tracing")
-	log.Printf("Service B URL: %s", serviceBURL)
+	log.Printf("Service B URL: %s", cfg.ServiceBURL)
 
 	go func() {
-		log.Println("Starting Service A on port", viper.GetString("HTTP_PORT"))
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Println("Starting Service A on port", cfg.HTTPPort)
+		var err error
+		if httpServer.TLSConfig != nil {
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server:", err)
 		}
 	}()
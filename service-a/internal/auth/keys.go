@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Key chave de API cadastrada; apenas o hash SHA-256 da chave é mantido
+type Key struct {
+	ID    string
+	Hash  string
+	Admin bool
+}
+
+// keyFileEntry entrada do arquivo de chaves; informe sha256 (preferível) ou key em texto
+type keyFileEntry struct {
+	ID     string `json:"id"`
+	SHA256 string `json:"sha256"`
+	Key    string `json:"key"`
+	Admin  bool   `json:"admin"`
+}
+
+// validKeyID IDs seguros para atributos de span e baggage
+var validKeyID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// KeyStore chaves indexadas pelo hash
+type KeyStore struct {
+	byHash map[string]Key
+}
+
+// HashKey retorna o hash SHA-256 (hex) de uma chave
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadKeys carrega as chaves do arquivo JSON ([{"id","sha256"|"key","admin"}]) e da
+// lista no formato id:segredo[:admin] ou id:sha256=<hex>[:admin], separada por vírgula
+func LoadKeys(file, list string) (*KeyStore, error) {
+	var entries []keyFileEntry
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read API keys file: %w", err)
+		}
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse API keys file %s: %w", file, err)
+		}
+	}
+
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != "admin") {
+			return nil, fmt.Errorf("invalid API key entry for %q (expected id:secret[:admin])", parts[0])
+		}
+		entry := keyFileEntry{ID: parts[0], Admin: len(parts) == 3}
+		if hash, ok := strings.CutPrefix(parts[1], "sha256="); ok {
+			entry.SHA256 = hash
+		} else {
+			entry.Key = parts[1]
+		}
+		entries = append(entries, entry)
+	}
+
+	store := &KeyStore{byHash: make(map[string]Key, len(entries))}
+	for _, entry := range entries {
+		if !validKeyID.MatchString(entry.ID) {
+			return nil, fmt.Errorf("invalid API key id %q (expected letters, digits, '.', '_' or '-')", entry.ID)
+		}
+
+		hash := strings.ToLower(entry.SHA256)
+		if hash == "" && entry.Key != "" {
+			hash = HashKey(entry.Key)
+		}
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("API key %s: missing or invalid sha256", entry.ID)
+		}
+		if existing, ok := store.byHash[hash]; ok {
+			return nil, fmt.Errorf("API keys %s and %s share the same secret", existing.ID, entry.ID)
+		}
+
+		store.byHash[hash] = Key{ID: entry.ID, Hash: hash, Admin: entry.Admin}
+	}
+
+	return store, nil
+}
+
+// Lookup procura a chave apresentada pelo cliente
+func (s *KeyStore) Lookup(presented string) (Key, bool) {
+	if presented == "" {
+		return Key{}, false
+	}
+	key, ok := s.byHash[HashKey(presented)]
+	return key, ok
+}
+
+// Len quantidade de chaves cadastradas
+func (s *KeyStore) Len() int {
+	return len(s.byHash)
+}
+
+// APIKey extrai a chave de API de Authorization: Bearer ou X-API-Key
+func APIKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// KeyIDAttribute atributo de span e membro de baggage com o ID da chave autenticada
+const KeyIDAttribute = "api.key.id"
+
+// ClientIDAttribute atributo de span e membro de baggage com o cliente autenticado
+const ClientIDAttribute = "client.id"
+
+// reservedBaggage membros de baggage que só a autenticação define
+var reservedBaggage = []string{KeyIDAttribute, ClientIDAttribute}
+
+// baggageHeader header W3C do baggage
+const baggageHeader = "baggage"
+
+type principalKey struct{}
+
+// principal chave autenticada da requisição
+type principal struct {
+	key   Key
+	usage *Usage
+}
+
+// KeyFromContext retorna a chave autenticada da requisição
+func KeyFromContext(ctx context.Context) (Key, bool) {
+	p, ok := ctx.Value(principalKey{}).(*principal)
+	if !ok {
+		return Key{}, false
+	}
+	return p.key, true
+}
+
+// Authenticator valida a chave de API e contabiliza o uso por chave
+type Authenticator struct {
+	keys  *KeyStore
+	usage *Usage
+}
+
+// NewAuthenticator cria um Authenticator
+func NewAuthenticator(keys *KeyStore, usage *Usage) *Authenticator {
+	return &Authenticator{keys: keys, usage: usage}
+}
+
+// Middleware exige uma chave válida (401 caso contrário), adiciona o ID da chave ao span
+// e ao baggage (api.key.id e client.id, propagados ao Serviço B) e registra o uso
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, ok := a.keys.Lookup(APIKey(r))
+		if !ok {
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.Bool("auth.rejected", true))
+			w.Header().Set("WWW-Authenticate", `Bearer realm="service-a"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), principalKey{}, &principal{key: key, usage: a.usage})
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String(KeyIDAttribute, key.ID), attribute.String(ClientIDAttribute, key.ID))
+		ctx = withBaggage(ctx, key.ID)
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		a.usage.record(key.ID, status)
+	})
+}
+
+// withBaggage define os membros reservados do baggage com o ID da chave autenticada
+func withBaggage(ctx context.Context, id string) context.Context {
+	bag := baggage.FromContext(ctx)
+	for _, name := range reservedBaggage {
+		member, err := baggage.NewMemberRaw(name, id)
+		if err != nil {
+			return ctx
+		}
+		if bag, err = bag.SetMember(member); err != nil {
+			return ctx
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// StripBaggage remove do header baggage recebido os membros que só a autenticação
+// define (api.key.id e client.id), para que um cliente não se passe por outra chave
+// nos spans e no Serviço B. Deve rodar antes da extração do contexto pelo tracing;
+// um header inválido é descartado, como faria o propagador.
+func StripBaggage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values := r.Header.Values(baggageHeader)
+		if len(values) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r.Header.Del(baggageHeader)
+		if bag, err := baggage.Parse(strings.Join(values, ",")); err == nil {
+			for _, name := range reservedBaggage {
+				bag = bag.DeleteMember(name)
+			}
+			if bag.Len() > 0 {
+				r.Header.Set(baggageHeader, bag.String())
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// HandleUsage lista o uso por chave (rota de administração)
+func (a *Authenticator) HandleUsage(w http.ResponseWriter, r *http.Request) {
+	a.usage.HandleUsage(w, r)
+}
+
+// RequireAdmin permite apenas chaves marcadas como admin (usar após Middleware)
+func (a *Authenticator) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key, ok := KeyFromContext(r.Context()); !ok || !key.Admin {
+			writeError(w, http.StatusForbidden, "admin API key required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// KeyUsage uso acumulado de uma chave desde o início do processo
+type KeyUsage struct {
+	ID            string    `json:"id"`
+	Requests      int64     `json:"requests"`
+	Errors        int64     `json:"errors"`
+	UpstreamCalls int64     `json:"upstream_calls"`
+	LastUsed      time.Time `json:"last_used"`
+}
+
+// Usage contabiliza o uso por chave (em memória, por réplica)
+type Usage struct {
+	mu    sync.Mutex
+	byKey map[string]*KeyUsage
+}
+
+// NewUsage cria um Usage vazio
+func NewUsage() *Usage {
+	return &Usage{byKey: make(map[string]*KeyUsage)}
+}
+
+// record registra uma requisição e se ela terminou em erro (status >= 400)
+func (u *Usage) record(id string, statusCode int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	usage := u.get(id)
+	usage.Requests++
+	if statusCode >= http.StatusBadRequest {
+		usage.Errors++
+	}
+	usage.LastUsed = time.Now()
+}
+
+// upstreamCall registra uma chamada ao Serviço B
+func (u *Usage) upstreamCall(id string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.get(id).UpstreamCalls++
+}
+
+// get retorna (criando se necessário) o uso da chave; requer o lock
+func (u *Usage) get(id string) *KeyUsage {
+	usage, ok := u.byKey[id]
+	if !ok {
+		usage = &KeyUsage{ID: id}
+		u.byKey[id] = usage
+	}
+	return usage
+}
+
+// Snapshot retorna uma cópia do uso ordenada por ID
+func (u *Usage) Snapshot() []KeyUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	snapshot := make([]KeyUsage, 0, len(u.byKey))
+	for _, usage := range u.byKey {
+		snapshot = append(snapshot, *usage)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].ID < snapshot[j].ID })
+
+	return snapshot
+}
+
+// HandleUsage lista o uso por chave em JSON
+func (u *Usage) HandleUsage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]any{"keys": u.Snapshot()})
+}
+
+// CountUpstreamCall registra uma chamada ao Serviço B para a chave autenticada no contexto
+func CountUpstreamCall(ctx context.Context) {
+	if p, ok := ctx.Value(principalKey{}).(*principal); ok {
+		p.usage.upstreamCall(p.key.ID)
+	}
+}
@@ -0,0 +1,652 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-a/internal/fault"
+	"github.com/marfebr/otel-lab/service-a/internal/httpclient"
+	"github.com/marfebr/otel-lab/service-a/internal/logging"
+	"github.com/marfebr/otel-lab/service-a/internal/peerauth"
+	"github.com/marfebr/otel-lab/service-a/internal/ratelimit"
+	"github.com/marfebr/otel-lab/service-a/internal/shed"
+	"github.com/marfebr/otel-lab/service-a/internal/telemetry"
+	"github.com/marfebr/otel-lab/service-a/internal/tlsconfig"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Config configuração tipada do serviço, carregada uma única vez na inicialização
+type Config struct {
+	HTTPPort string
+	// TLS certificado do listener HTTP (vazio serve HTTP puro)
+	TLS TLSConfig
+	// LogLevel nível mínimo dos logs (debug, info, warn, error)
+	LogLevel string
+	// ServiceBURL URL base do Serviço B
+	ServiceBURL string
+	// ServiceBTimeout limite de cada chamada ao Serviço B
+	ServiceBTimeout time.Duration
+	// RequestTimeout prazo de cada requisição, repassado ao Serviço B em X-Request-Timeout
+	RequestTimeout time.Duration
+	// HTTPClient pool de conexões e limites por fase das chamadas HTTP de saída
+	HTTPClient httpclient.Options
+	// Shed controle de admissão das requisições recebidas
+	Shed shed.Limits
+	// ServiceBCAFile CAs aceitas para o Serviço B em https (vazio usa as CAs do sistema)
+	ServiceBCAFile string
+	// ServiceBTransport protocolo das chamadas ao Serviço B: http (JSON) ou grpc
+	ServiceBTransport string
+	// ServiceBGRPCAddr endereço gRPC do Serviço B e ServiceBGRPCTLS se a conexão usa TLS
+	ServiceBGRPCAddr string
+	ServiceBGRPCTLS  bool
+	// HealthCacheTTL tempo durante o qual o resultado do /readyz é reaproveitado
+	HealthCacheTTL time.Duration
+	Telemetry      telemetry.Config
+	DebugTraces    DebugTracesConfig
+	Fault          FaultConfig
+	RateLimit      RateLimitConfig
+	Auth           AuthConfig
+	Idempotency    IdempotencyConfig
+	// ServiceAuth autenticação das chamadas ao Serviço B
+	ServiceAuth peerauth.Config
+
+	// file arquivo de configuração carregado (vazio se ausente)
+	file string
+	// values valores brutos de cada chave, usados para comparar recargas
+	values map[string]string
+}
+
+// AuthConfig configuração da autenticação por chave de API do POST /cep
+type AuthConfig struct {
+	Enabled bool
+	// KeysFile arquivo JSON de chaves e Keys lista id:segredo[:admin],...
+	KeysFile string
+	Keys     string
+}
+
+// RateLimitConfig configuração do rate limiting por cliente do POST /cep
+type RateLimitConfig struct {
+	Enabled bool
+	Limits  ratelimit.Limits
+	// Store onde os buckets ficam: memory (por réplica) ou redis (compartilhado)
+	Store    string
+	RedisURL string
+}
+
+// IdempotencyConfig configuração da deduplicação do POST /cep por Idempotency-Key
+type IdempotencyConfig struct {
+	Enabled bool
+	// TTL tempo durante o qual a primeira resposta de cada chave é reenviada
+	TTL time.Duration
+	// MaxKeys número máximo de chaves guardadas; cheio, a mais antiga é descartada
+	MaxKeys int
+}
+
+// TLSConfig configuração TLS do listener HTTP
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// MinVersion versão mínima aceita no listener e nas conexões de saída
+	MinVersion uint16
+}
+
+// DebugTracesConfig configuração da página /debug/traces
+type DebugTracesConfig struct {
+	Enabled  bool
+	MaxSpans int
+}
+
+// FaultConfig configuração da injeção de falhas (desativada por padrão)
+type FaultConfig struct {
+	Enabled bool
+	// Rules falhas por alvo; a requisição pode trazer outras no header X-Fault-Inject
+	Rules fault.Rules
+}
+
+// ErrHelp retornado por Load quando --help é solicitado
+var ErrHelp = pflag.ErrHelp
+
+// setting chave de configuração com valor padrão e descrição da flag
+type setting struct {
+	key   string
+	value string
+	usage string
+}
+
+// settings chaves aceitas; cada uma pode vir do ambiente, do arquivo de configuração
+// ou da flag equivalente (ex.: HTTP_PORT → --http-port), nesta ordem de precedência:
+// flag, ambiente, arquivo, padrão
+var settings = []setting{
+	{"HTTP_PORT", ":8080", "HTTP listen address"},
+	{"HTTP_TLS_CERT_FILE", "", "TLS certificate of the HTTP listener (empty serves plain HTTP)"},
+	{"HTTP_TLS_KEY_FILE", "", "TLS private key of the HTTP listener"},
+	{"TLS_MIN_VERSION", "1.2", "minimum TLS version of the listener and outbound connections (1.2, 1.3)"},
+	{"LOG_LEVEL", "info", "minimum log level (debug, info, warn, error)"},
+	{"SERVICE_B_URL", "http://service-b:8181", "service B base URL"},
+	{"SERVICE_B_TRANSPORT", "http", "protocol of the calls to service B (http, grpc)"},
+	{"SERVICE_B_GRPC_ADDR", "service-b:8282", "service B gRPC address (host:port)"},
+	{"SERVICE_B_GRPC_TLS", "false", "use TLS on the gRPC connection to service B"},
+	{"SERVICE_B_TIMEOUT", "30s", "timeout of each call to service B"},
+	{"REQUEST_TIMEOUT", "10s", "maximum time per request, propagated to service B (a shorter X-Request-Timeout from the caller wins)"},
+	{"HTTP_CLIENT_MAX_IDLE_CONNS", "100", "idle outbound connections kept across all hosts"},
+	{"HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", "20", "idle outbound connections kept per host"},
+	{"HTTP_CLIENT_MAX_CONNS_PER_HOST", "0", "concurrent outbound connections per host (0 disables)"},
+	{"HTTP_CLIENT_IDLE_CONN_TIMEOUT", "90s", "how long an idle outbound connection is kept"},
+	{"HTTP_CLIENT_DIAL_TIMEOUT", "3s", "timeout to open an outbound TCP connection"},
+	{"HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT", "5s", "timeout of the outbound TLS handshake"},
+	{"HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT", "30s", "timeout waiting for the response headers of an outbound call"},
+	{"SHED_MAX_IN_FLIGHT", "200", "requests served at the same time; health checks and metrics are always admitted (0 disables admission control)"},
+	{"SHED_MAX_QUEUE", "100", "requests that may wait for admission"},
+	{"SHED_MAX_QUEUE_TIME", "100ms", "how long a request may wait for admission before being shed with 503"},
+	{"SHED_LOW_PRIORITY_SHARE", "0.5", "share of SHED_MAX_IN_FLIGHT usable by low priority routes (/debug, /admin)"},
+	{"SERVICE_B_CA_FILE", "", "CA bundle trusted for an https SERVICE_B_URL (empty uses the system CAs)"},
+	{"HEALTH_CACHE_TTL", "5s", "how long readiness results are cached"},
+	{"OTEL_SERVICE_NAME", "service-a", "service name reported in telemetry"},
+	{"OTEL_EXPORTER_OTLP_ENDPOINT", "otel-collector:4317", "OTLP gRPC endpoint (host:port)"},
+	{"OTEL_EXPORTER_OTLP_HTTP_ENDPOINT", "otel-collector:4318", "OTLP HTTP endpoint (host:port)"},
+	{"OTEL_EXPORTER_OTLP_INSECURE", "true", "connect to the collector without TLS"},
+	{"OTEL_EXPORTER_OTLP_CERTIFICATE", "", "CA bundle trusted for the collector (empty uses the system CAs)"},
+	{"OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE", "", "client certificate presented to the collector"},
+	{"OTEL_EXPORTER_OTLP_CLIENT_KEY", "", "client private key presented to the collector"},
+	{"OTEL_EXPORTER_OTLP_SERVER_NAME", "", "expected server name in the collector certificate"},
+	{"OTEL_EXPORTER_ZIPKIN_ENDPOINT", "http://zipkin:9411/api/v2/spans", "Zipkin spans URL"},
+	{"OTEL_TRACES_EXPORTER", "otlp-grpc", "span exporters (otlp-grpc, otlp-http, zipkin, stdout, file, none)"},
+	{"OTEL_EXPORTER_FILE_DIR", "./telemetry", "directory of the file exporter"},
+	{"OTEL_EXPORTER_FILE_MAX_BYTES", "10485760", "file exporter rotation size in bytes (0 disables)"},
+	{"OTEL_EXPORTER_FILE_MAX_BACKUPS", "5", "rotated files kept by the file exporter"},
+	{"OTEL_EXPORTER_FILE_METRICS_INTERVAL", "30s", "interval between metric snapshots of the file exporter"},
+	{"OTEL_TRACES_SAMPLER", "parentbased_always_on", "trace sampler"},
+	{"OTEL_TRACES_SAMPLER_ARG", "", "sampler argument (ratio)"},
+	{"OTEL_TRACES_SAMPLER_KEEP_ERRORS", "false", "always keep spans with errors"},
+	{"OTEL_TRACES_SAMPLER_KEEP_LATENCY", "0s", "always keep spans slower than this (0 disables)"},
+	{"OTEL_PROPAGATORS", "tracecontext,baggage", "context propagators"},
+	{"BAGGAGE_SPAN_ATTRIBUTES", "client.id,tenant.id,api.key.id", "baggage keys copied to span attributes"},
+	{"AUTH_ENABLED", "false", "require an API key on POST /cep"},
+	{"API_KEYS_FILE", "", "JSON file with API keys: [{\"id\",\"sha256\"|\"key\",\"admin\"}]"},
+	{"API_KEYS", "", "API keys: id:secret[:admin] or id:sha256=<hex>[:admin], comma separated"},
+	{"SERVICE_AUTH_MODE", "none", "service-to-service authentication (none, mtls, hmac)"},
+	{"SERVICE_AUTH_ID", "service-a", "identity of this service in hmac tokens"},
+	{"SERVICE_AUTH_SECRET", "", "shared HMAC secret of hmac mode (at least 32 characters)"},
+	{"SERVICE_AUTH_TOKEN_TTL", "30s", "validity of the hmac tokens sent to service B"},
+	{"SERVICE_AUTH_CA_FILE", "", "CA bundle that signs the service certificates (mtls mode)"},
+	{"SERVICE_AUTH_CERT_FILE", "", "client certificate (mtls mode)"},
+	{"SERVICE_AUTH_KEY_FILE", "", "client private key (mtls mode)"},
+	{"RATE_LIMIT_ENABLED", "false", "rate limit POST /cep per client"},
+	{"RATE_LIMIT_RPS", "10", "tokens per second refilled in each client bucket"},
+	{"RATE_LIMIT_BURST", "20", "bucket size (maximum burst) of each client"},
+	{"RATE_LIMIT_PER_KEY", "", "limits per authenticated API key ID: id=rps:burst,..."},
+	{"RATE_LIMIT_STORE", "memory", "rate limit bucket store (memory, redis)"},
+	{"RATE_LIMIT_REDIS_URL", "redis://redis:6379/0", "redis URL of the shared rate limit store"},
+	{"IDEMPOTENCY_ENABLED", "false", "replay the first response of POST /cep for repeated Idempotency-Key headers"},
+	{"IDEMPOTENCY_TTL", "1h", "how long the response of each Idempotency-Key is kept"},
+	{"IDEMPOTENCY_MAX_KEYS", "10000", "maximum Idempotency-Key responses kept; the oldest is dropped when full"},
+	{"DEBUG_TRACES_ENABLED", "false", "serve the /debug/traces pages"},
+	{"DEBUG_TRACES_MAX_SPANS", "1000", "finished spans kept for /debug/traces"},
+	{"FAULT_INJECTION_ENABLED", "false", "inject the faults of FAULT_RULES and of the X-Fault-Inject header (testing only)"},
+	{"FAULT_RULES", "", "faults per target as target:kind=value,... with kinds delay, error (rate) and status; targets are routes (/cep) and the service-b upstream"},
+}
+
+// Load lê a configuração do ambiente, do arquivo indicado por --config (ou CONFIG_FILE)
+// e das flags em args e a valida. Valores mal formados e fora dos limites são
+// reportados juntos.
+func Load(args []string) (*Config, error) {
+	v := viper.New()
+	v.AutomaticEnv()
+
+	flags := pflag.NewFlagSet("service-a", pflag.ContinueOnError)
+	configFile := flags.String("config", "", "configuration file (yaml, json, toml or env); also CONFIG_FILE")
+	for _, s := range settings {
+		v.SetDefault(s.key, s.value)
+		flags.String(flagName(s.key), s.value, s.usage)
+		if err := v.BindPFlag(s.key, flags.Lookup(flagName(s.key))); err != nil {
+			return nil, err
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile == "" {
+		*configFile = v.GetString("CONFIG_FILE")
+	}
+	if *configFile != "" {
+		v.SetConfigFile(*configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", *configFile, err)
+		}
+	}
+
+	p := &parser{v: v}
+	minTLSVersion := p.tlsVersion("TLS_MIN_VERSION")
+	cfg := &Config{
+		HTTPPort: v.GetString("HTTP_PORT"),
+		TLS: TLSConfig{
+			CertFile:   v.GetString("HTTP_TLS_CERT_FILE"),
+			KeyFile:    v.GetString("HTTP_TLS_KEY_FILE"),
+			MinVersion: minTLSVersion,
+		},
+		LogLevel:          v.GetString("LOG_LEVEL"),
+		ServiceBURL:       v.GetString("SERVICE_B_URL"),
+		ServiceBTimeout:   p.duration("SERVICE_B_TIMEOUT"),
+		ServiceBCAFile:    v.GetString("SERVICE_B_CA_FILE"),
+		ServiceBTransport: strings.ToLower(v.GetString("SERVICE_B_TRANSPORT")),
+		ServiceBGRPCAddr:  v.GetString("SERVICE_B_GRPC_ADDR"),
+		ServiceBGRPCTLS:   p.bool("SERVICE_B_GRPC_TLS"),
+		HealthCacheTTL:    p.duration("HEALTH_CACHE_TTL"),
+		HTTPClient: httpclient.Options{
+			MaxIdleConns:          p.int("HTTP_CLIENT_MAX_IDLE_CONNS"),
+			MaxIdleConnsPerHost:   p.int("HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST"),
+			MaxConnsPerHost:       p.int("HTTP_CLIENT_MAX_CONNS_PER_HOST"),
+			IdleConnTimeout:       p.duration("HTTP_CLIENT_IDLE_CONN_TIMEOUT"),
+			DialTimeout:           p.duration("HTTP_CLIENT_DIAL_TIMEOUT"),
+			TLSHandshakeTimeout:   p.duration("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT"),
+			ResponseHeaderTimeout: p.duration("HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT"),
+		},
+		Shed: shed.Limits{
+			MaxInFlight:      p.int("SHED_MAX_IN_FLIGHT"),
+			MaxQueue:         p.int("SHED_MAX_QUEUE"),
+			MaxQueueTime:     p.duration("SHED_MAX_QUEUE_TIME"),
+			LowPriorityShare: p.float("SHED_LOW_PRIORITY_SHARE"),
+		},
+		RequestTimeout: p.duration("REQUEST_TIMEOUT"),
+		Telemetry: telemetry.Config{
+			ServiceName:      v.GetString("OTEL_SERVICE_NAME"),
+			Exporters:        telemetry.SplitList(strings.ToLower(v.GetString("OTEL_TRACES_EXPORTER"))),
+			OTLPGRPCEndpoint: v.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"),
+			OTLPHTTPEndpoint: v.GetString("OTEL_EXPORTER_OTLP_HTTP_ENDPOINT"),
+			ZipkinEndpoint:   v.GetString("OTEL_EXPORTER_ZIPKIN_ENDPOINT"),
+			File: telemetry.FileConfig{
+				Dir:             v.GetString("OTEL_EXPORTER_FILE_DIR"),
+				MaxBytes:        int64(p.int("OTEL_EXPORTER_FILE_MAX_BYTES")),
+				MaxBackups:      p.int("OTEL_EXPORTER_FILE_MAX_BACKUPS"),
+				MetricsInterval: p.duration("OTEL_EXPORTER_FILE_METRICS_INTERVAL"),
+			},
+			Sampler: telemetry.SamplerConfig{
+				Name:        v.GetString("OTEL_TRACES_SAMPLER"),
+				Arg:         v.GetString("OTEL_TRACES_SAMPLER_ARG"),
+				KeepErrors:  p.bool("OTEL_TRACES_SAMPLER_KEEP_ERRORS"),
+				KeepLatency: p.duration("OTEL_TRACES_SAMPLER_KEEP_LATENCY"),
+			},
+			Propagators: v.GetString("OTEL_PROPAGATORS"),
+			BaggageKeys: telemetry.SplitList(v.GetString("BAGGAGE_SPAN_ATTRIBUTES")),
+			OTLPTLS: telemetry.OTLPTLSConfig{
+				Insecure:   p.bool("OTEL_EXPORTER_OTLP_INSECURE"),
+				CAFile:     v.GetString("OTEL_EXPORTER_OTLP_CERTIFICATE"),
+				CertFile:   v.GetString("OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE"),
+				KeyFile:    v.GetString("OTEL_EXPORTER_OTLP_CLIENT_KEY"),
+				ServerName: v.GetString("OTEL_EXPORTER_OTLP_SERVER_NAME"),
+				MinVersion: minTLSVersion,
+			},
+		},
+		DebugTraces: DebugTracesConfig{
+			Enabled:  p.bool("DEBUG_TRACES_ENABLED"),
+			MaxSpans: p.int("DEBUG_TRACES_MAX_SPANS"),
+		},
+		Fault: FaultConfig{
+			Enabled: p.bool("FAULT_INJECTION_ENABLED"),
+			Rules:   p.faults("FAULT_RULES"),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: p.bool("RATE_LIMIT_ENABLED"),
+			Limits: ratelimit.Limits{
+				Default: ratelimit.Limit{
+					Rate:  p.float("RATE_LIMIT_RPS"),
+					Burst: p.int("RATE_LIMIT_BURST"),
+				},
+				PerKey: p.limits("RATE_LIMIT_PER_KEY"),
+			},
+			Store:    strings.ToLower(v.GetString("RATE_LIMIT_STORE")),
+			RedisURL: v.GetString("RATE_LIMIT_REDIS_URL"),
+		},
+		Auth: AuthConfig{
+			Enabled:  p.bool("AUTH_ENABLED"),
+			KeysFile: v.GetString("API_KEYS_FILE"),
+			Keys:     v.GetString("API_KEYS"),
+		},
+		Idempotency: IdempotencyConfig{
+			Enabled: p.bool("IDEMPOTENCY_ENABLED"),
+			TTL:     p.duration("IDEMPOTENCY_TTL"),
+			MaxKeys: p.int("IDEMPOTENCY_MAX_KEYS"),
+		},
+		ServiceAuth: peerauth.Config{
+			Mode:       strings.ToLower(v.GetString("SERVICE_AUTH_MODE")),
+			ID:         v.GetString("SERVICE_AUTH_ID"),
+			Secret:     v.GetString("SERVICE_AUTH_SECRET"),
+			TokenTTL:   p.duration("SERVICE_AUTH_TOKEN_TTL"),
+			CAFile:     v.GetString("SERVICE_AUTH_CA_FILE"),
+			CertFile:   v.GetString("SERVICE_AUTH_CERT_FILE"),
+			KeyFile:    v.GetString("SERVICE_AUTH_KEY_FILE"),
+			MinVersion: minTLSVersion,
+		},
+		file:   *configFile,
+		values: make(map[string]string, len(settings)),
+	}
+	for _, s := range settings {
+		cfg.values[s.key] = v.GetString(s.key)
+	}
+
+	if err := errors.Join(p.errs...); err != nil {
+		return nil, errors.Join(err, cfg.Validate())
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate verifica endereços, portas e limites, reportando todos os problemas de uma vez
+func (c *Config) Validate() error {
+	var errs []error
+
+	errs = append(errs, validateListenAddr("HTTP_PORT", c.HTTPPort))
+	errs = append(errs, validatePair("HTTP_TLS_CERT_FILE", c.TLS.CertFile, "HTTP_TLS_KEY_FILE", c.TLS.KeyFile))
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+	}
+	errs = append(errs, validateServiceB(c))
+	errs = append(errs, validateRange("SERVICE_B_TIMEOUT", c.ServiceBTimeout, time.Millisecond, 5*time.Minute))
+	errs = append(errs, validateRange("REQUEST_TIMEOUT", c.RequestTimeout, time.Millisecond, 5*time.Minute))
+	errs = append(errs, validateHTTPClient(c.HTTPClient))
+	errs = append(errs, validateShed(c.Shed))
+	errs = append(errs, validateRange("HEALTH_CACHE_TTL", c.HealthCacheTTL, 0, 5*time.Minute))
+	errs = append(errs, validateTelemetry(c.Telemetry))
+
+	errs = append(errs, validateServiceAuth(c.ServiceAuth))
+
+	if c.Auth.Enabled && c.Auth.KeysFile == "" && c.Auth.Keys == "" {
+		errs = append(errs, errors.New("API_KEYS_FILE/API_KEYS: at least one is required when AUTH_ENABLED=true"))
+	}
+
+	if c.RateLimit.Enabled {
+		errs = append(errs, validateRateLimit(c.RateLimit))
+	}
+
+	if c.Idempotency.Enabled {
+		errs = append(errs, validateRange("IDEMPOTENCY_TTL", c.Idempotency.TTL, time.Second, 7*24*time.Hour))
+		if c.Idempotency.MaxKeys <= 0 {
+			errs = append(errs, fmt.Errorf("IDEMPOTENCY_MAX_KEYS: must be positive, got %d", c.Idempotency.MaxKeys))
+		}
+	}
+
+	if c.DebugTraces.Enabled && c.DebugTraces.MaxSpans <= 0 {
+		errs = append(errs, fmt.Errorf("DEBUG_TRACES_MAX_SPANS: must be positive, got %d", c.DebugTraces.MaxSpans))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateServiceB valida o endereço do transporte escolhido; no modo mtls a conexão
+// precisa usar TLS
+func validateServiceB(c *Config) error {
+	mtls := c.ServiceAuth.Mode == peerauth.ModeMTLS
+	switch c.ServiceBTransport {
+	case "http":
+		if mtls && !strings.HasPrefix(c.ServiceBURL, "https://") {
+			return errors.New("SERVICE_B_URL: must use https when SERVICE_AUTH_MODE=mtls")
+		}
+		return validateURL("SERVICE_B_URL", c.ServiceBURL)
+	case "grpc":
+		if mtls && !c.ServiceBGRPCTLS {
+			return errors.New("SERVICE_B_GRPC_TLS: must be true when SERVICE_AUTH_MODE=mtls")
+		}
+		return validateHostPort("SERVICE_B_GRPC_ADDR", c.ServiceBGRPCAddr)
+	default:
+		return fmt.Errorf("SERVICE_B_TRANSPORT: unknown transport %q (expected http or grpc)", c.ServiceBTransport)
+	}
+}
+
+// validateServiceAuth exige os certificados no modo mtls e o segredo no modo hmac
+func validateServiceAuth(cfg peerauth.Config) error {
+	switch cfg.Mode {
+	case peerauth.ModeNone:
+		return nil
+	case peerauth.ModeMTLS:
+		if cfg.CAFile == "" || cfg.CertFile == "" || cfg.KeyFile == "" {
+			return errors.New("SERVICE_AUTH_CA_FILE/SERVICE_AUTH_CERT_FILE/SERVICE_AUTH_KEY_FILE: required when SERVICE_AUTH_MODE=mtls")
+		}
+		return nil
+	case peerauth.ModeHMAC:
+		var errs []error
+		if len(cfg.Secret) < 32 {
+			errs = append(errs, errors.New("SERVICE_AUTH_SECRET: at least 32 characters required when SERVICE_AUTH_MODE=hmac"))
+		}
+		if !peerauth.ValidID(cfg.ID) {
+			errs = append(errs, fmt.Errorf("SERVICE_AUTH_ID: invalid id %q (expected letters, digits, '.', '_' or '-')", cfg.ID))
+		}
+		errs = append(errs, validateRange("SERVICE_AUTH_TOKEN_TTL", cfg.TokenTTL, time.Second, 10*time.Minute))
+		return errors.Join(errs...)
+	default:
+		return fmt.Errorf("SERVICE_AUTH_MODE: unknown mode %q (expected none, mtls or hmac)", cfg.Mode)
+	}
+}
+
+// validateRateLimit exige limites positivos e um store conhecido
+func validateRateLimit(cfg RateLimitConfig) error {
+	var errs []error
+
+	errs = append(errs, validateLimit("RATE_LIMIT_RPS/RATE_LIMIT_BURST", cfg.Limits.Default))
+	for key, limit := range cfg.Limits.PerKey {
+		errs = append(errs, validateLimit("RATE_LIMIT_PER_KEY["+key+"]", limit))
+	}
+
+	switch cfg.Store {
+	case "memory":
+	case "redis":
+		u, err := url.Parse(cfg.RedisURL)
+		if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+			errs = append(errs, errors.New("RATE_LIMIT_REDIS_URL: invalid URL (expected redis://[:password@]host:port/db)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_STORE: unknown store %q (expected memory or redis)", cfg.Store))
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateLimit(key string, limit ratelimit.Limit) error {
+	if limit.Rate <= 0 || limit.Burst < 1 {
+		return fmt.Errorf("%s: rate must be positive and burst at least 1, got %v:%d", key, limit.Rate, limit.Burst)
+	}
+	return nil
+}
+
+// validateHTTPClient exige tamanhos de pool não negativos e limites de tempo positivos
+func validateHTTPClient(opts httpclient.Options) error {
+	var errs []error
+	if opts.MaxIdleConns < 0 || opts.MaxIdleConnsPerHost < 0 || opts.MaxConnsPerHost < 0 {
+		errs = append(errs, errors.New("HTTP_CLIENT_MAX_IDLE_CONNS/HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST/HTTP_CLIENT_MAX_CONNS_PER_HOST: must not be negative"))
+	}
+	errs = append(errs, validateRange("HTTP_CLIENT_IDLE_CONN_TIMEOUT", opts.IdleConnTimeout, time.Second, 10*time.Minute))
+	errs = append(errs, validateRange("HTTP_CLIENT_DIAL_TIMEOUT", opts.DialTimeout, time.Millisecond, time.Minute))
+	errs = append(errs, validateRange("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT", opts.TLSHandshakeTimeout, time.Millisecond, time.Minute))
+	errs = append(errs, validateRange("HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT", opts.ResponseHeaderTimeout, time.Millisecond, 5*time.Minute))
+	return errors.Join(errs...)
+}
+
+// validateShed exige limites não negativos e fração de baixa prioridade entre 0 e 1
+func validateShed(limits shed.Limits) error {
+	var errs []error
+	if limits.MaxInFlight < 0 || limits.MaxQueue < 0 {
+		errs = append(errs, errors.New("SHED_MAX_IN_FLIGHT/SHED_MAX_QUEUE: must not be negative"))
+	}
+	errs = append(errs, validateRange("SHED_MAX_QUEUE_TIME", limits.MaxQueueTime, 0, time.Minute))
+	if limits.LowPriorityShare < 0 || limits.LowPriorityShare > 1 {
+		errs = append(errs, fmt.Errorf("SHED_LOW_PRIORITY_SHARE: must be between 0 and 1, got %g", limits.LowPriorityShare))
+	}
+	return errors.Join(errs...)
+}
+
+// validateTelemetry valida apenas os endpoints dos exportadores selecionados
+func validateTelemetry(cfg telemetry.Config) error {
+	var errs []error
+
+	for _, exporter := range cfg.Exporters {
+		switch exporter {
+		case telemetry.ExporterOTLPGRPC, "otlp":
+			errs = append(errs, validateHostPort("OTEL_EXPORTER_OTLP_ENDPOINT", cfg.OTLPGRPCEndpoint))
+		case telemetry.ExporterOTLPHTTP:
+			errs = append(errs, validateHostPort("OTEL_EXPORTER_OTLP_HTTP_ENDPOINT", cfg.OTLPHTTPEndpoint))
+		case telemetry.ExporterZipkin:
+			errs = append(errs, validateURL("OTEL_EXPORTER_ZIPKIN_ENDPOINT", cfg.ZipkinEndpoint))
+		case telemetry.ExporterFile:
+			if cfg.File.Dir == "" {
+				errs = append(errs, errors.New("OTEL_EXPORTER_FILE_DIR: must not be empty"))
+			}
+			if cfg.File.MaxBytes < 0 || cfg.File.MaxBackups < 0 {
+				errs = append(errs, errors.New("OTEL_EXPORTER_FILE_MAX_BYTES/OTEL_EXPORTER_FILE_MAX_BACKUPS: must not be negative"))
+			}
+			errs = append(errs, validateRange("OTEL_EXPORTER_FILE_METRICS_INTERVAL", cfg.File.MetricsInterval, time.Second, time.Hour))
+		}
+	}
+	if !cfg.OTLPTLS.Insecure {
+		errs = append(errs, validatePair("OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE", cfg.OTLPTLS.CertFile, "OTEL_EXPORTER_OTLP_CLIENT_KEY", cfg.OTLPTLS.KeyFile))
+	}
+	if err := telemetry.ValidateExporters(cfg.Exporters); err != nil {
+		errs = append(errs, fmt.Errorf("OTEL_TRACES_EXPORTER: %w", err))
+	}
+	if _, err := telemetry.NewPropagator(cfg.Propagators); err != nil {
+		errs = append(errs, fmt.Errorf("OTEL_PROPAGATORS: %w", err))
+	}
+	if _, err := telemetry.NewSampler(cfg.Sampler.Name, cfg.Sampler.Arg); err != nil {
+		errs = append(errs, fmt.Errorf("OTEL_TRACES_SAMPLER/OTEL_TRACES_SAMPLER_ARG: %w", err))
+	}
+	errs = append(errs, validateRange("OTEL_TRACES_SAMPLER_KEEP_LATENCY", cfg.Sampler.KeepLatency, 0, time.Minute))
+
+	return errors.Join(errs...)
+}
+
+// validateListenAddr aceita "host:porta" ou ":porta"
+func validateListenAddr(key, addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%s: invalid listen address %q (expected [host]:port)", key, addr)
+	}
+	return validatePort(key, port)
+}
+
+// validateHostPort exige host e porta (ex.: otel-collector:4317)
+func validateHostPort(key, addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return fmt.Errorf("%s: invalid endpoint %q (expected host:port)", key, addr)
+	}
+	return validatePort(key, port)
+}
+
+func validatePort(key, port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%s: invalid port %q (expected 1-65535)", key, port)
+	}
+	return nil
+}
+
+// validateURL exige URL absoluta http(s) com host
+func validateURL(key, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s: invalid URL %q (expected http(s)://host[:port][/path])", key, raw)
+	}
+	if u.Port() != "" {
+		return validatePort(key, u.Port())
+	}
+	return nil
+}
+
+// validatePair exige que os dois valores (ex.: certificado e chave) sejam informados juntos
+func validatePair(key1, value1, key2, value2 string) error {
+	if (value1 == "") != (value2 == "") {
+		return fmt.Errorf("%s/%s: both must be set together", key1, key2)
+	}
+	return nil
+}
+
+func validateRange(key string, d, min, max time.Duration) error {
+	if d < min || d > max {
+		return fmt.Errorf("%s: %s out of range [%s, %s]", key, d, min, max)
+	}
+	return nil
+}
+
+// flagName converte a chave para o nome da flag (HTTP_PORT → http-port)
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// parser converte valores acumulando os erros de formato
+type parser struct {
+	v    *viper.Viper
+	errs []error
+}
+
+func (p *parser) duration(key string) time.Duration {
+	d, err := time.ParseDuration(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid duration %q", key, p.v.GetString(key)))
+	}
+	return d
+}
+
+func (p *parser) float(key string) float64 {
+	f, err := strconv.ParseFloat(p.v.GetString(key), 64)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid number %q", key, p.v.GetString(key)))
+	}
+	return f
+}
+
+// limits lê limites no formato id=rps:burst separados por vírgula
+func (p *parser) limits(key string) map[string]ratelimit.Limit {
+	limits := make(map[string]ratelimit.Limit)
+	for _, entry := range telemetry.SplitList(p.v.GetString(key)) {
+		id, spec, ok := strings.Cut(entry, "=")
+		rate, burst, ok2 := strings.Cut(spec, ":")
+		r, err := strconv.ParseFloat(rate, 64)
+		b, err2 := strconv.Atoi(burst)
+		if !ok || !ok2 || id == "" || err != nil || err2 != nil {
+			p.errs = append(p.errs, fmt.Errorf("%s: invalid entry %q (expected id=rps:burst)", key, entry))
+			continue
+		}
+		limits[id] = ratelimit.Limit{Rate: r, Burst: b}
+	}
+	return limits
+}
+
+func (p *parser) faults(key string) fault.Rules {
+	rules, err := fault.ParseRules(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: %w", key, err))
+	}
+	return rules
+}
+
+func (p *parser) int(key string) int {
+	n, err := strconv.Atoi(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid integer %q", key, p.v.GetString(key)))
+	}
+	return n
+}
+
+func (p *parser) tlsVersion(key string) uint16 {
+	version, err := tlsconfig.ParseVersion(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: %w", key, err))
+	}
+	return version
+}
+
+func (p *parser) bool(key string) bool {
+	b, err := strconv.ParseBool(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid boolean %q", key, p.v.GetString(key)))
+	}
+	return b
+}
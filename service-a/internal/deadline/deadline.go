@@ -0,0 +1,111 @@
+// Package deadline propaga o prazo da requisição entre os serviços: quem chama envia
+// o tempo restante no header X-Request-Timeout e quem recebe o converte no deadline
+// do contexto. O valor é relativo, como o grpc-timeout, para não depender dos
+// relógios dos dois lados estarem sincronizados.
+package deadline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header tempo restante da requisição, em milissegundos
+const Header = "X-Request-Timeout"
+
+// timeoutKey atributo de span com o prazo aplicado à requisição
+const timeoutKey = attribute.Key("request.timeout_ms")
+
+// exceededError prazo esgotado; equivale a context.DeadlineExceeded em errors.Is
+type exceededError struct{}
+
+func (exceededError) Error() string { return "request deadline exceeded" }
+
+func (exceededError) Is(target error) bool { return target == context.DeadlineExceeded }
+
+// ErrExceeded retornado quando não resta tempo para concluir a requisição
+var ErrExceeded error = exceededError{}
+
+// Parse lê o tempo restante informado no header (milissegundos, não negativo)
+func Parse(value string) (time.Duration, error) {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms < 0 {
+		return 0, fmt.Errorf("invalid %s %q (expected milliseconds)", Header, value)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// Format representa o tempo restante no formato do header, arredondado para cima
+func Format(remaining time.Duration) string {
+	return strconv.FormatInt(int64((remaining+time.Millisecond-1)/time.Millisecond), 10)
+}
+
+// Middleware aplica à requisição o prazo do header, limitado a max, que também é o
+// prazo quando o header está ausente. Header inválido recebe 400 e prazo já
+// esgotado recebe 504 sem chegar ao handler.
+func Middleware(max time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := max
+			if value := r.Header.Get(Header); value != "" {
+				requested, err := Parse(value)
+				if err != nil {
+					writeError(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				timeout = min(requested, max)
+			}
+			trace.SpanFromContext(r.Context()).SetAttributes(timeoutKey.Int64(timeout.Milliseconds()))
+			if timeout <= 0 {
+				writeError(w, ErrExceeded.Error(), http.StatusGatewayTimeout)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// NewTransport envia em cada requisição o tempo restante até o deadline do contexto
+// e falha sem chamar o upstream se ele já passou
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	d, ok := req.Context().Deadline()
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	remaining := time.Until(d)
+	if remaining <= 0 {
+		return nil, ErrExceeded
+	}
+
+	// RoundTrip não pode alterar a requisição recebida
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, Format(remaining))
+	return t.base.RoundTrip(req)
+}
+
+// writeError responde no mesmo formato de erro dos handlers
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,285 @@
+// Package fault injeta falhas controladas (atraso e erros) nas rotas e nas chamadas
+// de saída, para observar nos traces como os serviços se comportam quando algo
+// falha. As regras vêm da configuração ou, por requisição, do header X-Fault-Inject,
+// que segue para o serviço seguinte. Toda falha injetada é marcada no span.
+package fault
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-a/internal/deadline"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Header regras de falha da requisição, no mesmo formato de FAULT_RULES
+const Header = "X-Fault-Inject"
+
+// metadataKey equivalente do header nas chamadas gRPC
+const metadataKey = "x-fault-inject"
+
+// ErrInjected erro das falhas injetadas
+var ErrInjected = errors.New("injected fault")
+
+// Atributos dos spans com falha injetada
+const (
+	injectedKey = attribute.Key("fault.injected")
+	targetKey   = attribute.Key("fault.target")
+	typeKey     = attribute.Key("fault.type")
+	delayKey    = attribute.Key("fault.delay_ms")
+	errorKey    = attribute.Key("fault.error")
+)
+
+// faultsInjected contador de falhas injetadas por alvo e tipo
+var faultsInjected = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "faults_injected_total",
+	Help: "Injected faults by target (route, gRPC method or upstream) and type (delay, error).",
+}, []string{"target", "type"})
+
+// Fault falha de um alvo
+type Fault struct {
+	// Delay atraso adicionado antes de atender a rota ou fazer a chamada
+	Delay time.Duration
+	// ErrorRate probabilidade (0 a 1) de falhar
+	ErrorRate float64
+	// Status status HTTP das falhas injetadas em rotas (padrão 500)
+	Status int
+}
+
+// Rules falhas por alvo: rota (ex.: /cep), método gRPC ou upstream (ex.: viacep)
+type Rules map[string]Fault
+
+// ParseRules lê regras no formato alvo:tipo=valor,... com os tipos delay (duração),
+// error (probabilidade) e status (400 a 599), ex.: /cep:delay=200ms,viacep:error=0.5
+func ParseRules(spec string) (Rules, error) {
+	rules := make(Rules)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		sep := strings.LastIndex(name, ":")
+		if !ok || sep <= 0 {
+			return nil, fmt.Errorf("invalid fault rule %q (expected target:kind=value)", entry)
+		}
+		target, kind := name[:sep], name[sep+1:]
+
+		f := rules[target]
+		var err error
+		switch kind {
+		case "delay":
+			f.Delay, err = time.ParseDuration(value)
+			if err == nil && f.Delay < 0 {
+				err = errors.New("must not be negative")
+			}
+		case "error":
+			f.ErrorRate, err = strconv.ParseFloat(value, 64)
+			if err == nil && (f.ErrorRate < 0 || f.ErrorRate > 1) {
+				err = errors.New("must be between 0 and 1")
+			}
+		case "status":
+			f.Status, err = strconv.Atoi(value)
+			if err == nil && (f.Status < 400 || f.Status > 599) {
+				err = errors.New("must be between 400 and 599")
+			}
+		default:
+			err = errors.New("unknown kind (delay, error, status)")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid fault rule %q: %w", entry, err)
+		}
+		rules[target] = f
+	}
+	return rules, nil
+}
+
+// requestKey chave das regras do header no contexto
+type requestKey struct{}
+
+// requested regras do header da requisição e o valor original, repassado adiante
+type requested struct {
+	raw   string
+	rules Rules
+}
+
+// Injector aplica as regras da configuração e as do header da requisição, que
+// prevalecem para o mesmo alvo. Um Injector nil não injeta falhas nem lê o header.
+type Injector struct {
+	rules atomic.Pointer[Rules]
+}
+
+// NewInjector cria o Injector com as regras da configuração
+func NewInjector(rules Rules) *Injector {
+	i := &Injector{}
+	i.SetRules(rules)
+	return i
+}
+
+// SetRules troca as regras da configuração (recarga de configuração)
+func (i *Injector) SetRules(rules Rules) {
+	if i == nil {
+		return
+	}
+	i.rules.Store(&rules)
+}
+
+// Middleware lê o header X-Fault-Inject (inválido recebe 400) e aplica a falha da
+// rota; o erro injetado responde com o status da regra sem chegar ao handler
+func (i *Injector) Middleware(next http.Handler) http.Handler {
+	if i == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if raw := r.Header.Get(Header); raw != "" {
+			rules, err := ParseRules(raw)
+			if err != nil {
+				writeError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ctx = context.WithValue(ctx, requestKey{}, &requested{raw: raw, rules: rules})
+			r = r.WithContext(ctx)
+		}
+
+		f, err := i.apply(ctx, r.URL.Path)
+		switch {
+		case errors.Is(err, ErrInjected):
+			writeError(w, ErrInjected.Error(), cmp.Or(f.Status, http.StatusInternalServerError))
+			return
+		case err != nil:
+			writeError(w, deadline.ErrExceeded.Error(), http.StatusGatewayTimeout)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Transport aplica a falha do upstream a cada chamada feita por base; o erro
+// injetado é retornado no lugar da resposta
+func (i *Injector) Transport(upstream string, base http.RoundTripper) http.RoundTripper {
+	if i == nil {
+		return base
+	}
+	return &transport{injector: i, upstream: upstream, base: base}
+}
+
+type transport struct {
+	injector *Injector
+	upstream string
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, err := t.injector.apply(req.Context(), t.upstream); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Propagate repassa o header X-Fault-Inject da requisição às chamadas feitas por
+// base, para que o serviço chamado aplique as regras dos seus alvos
+func (i *Injector) Propagate(base http.RoundTripper) http.RoundTripper {
+	if i == nil {
+		return base
+	}
+	return propagator{base: base}
+}
+
+type propagator struct {
+	base http.RoundTripper
+}
+
+func (p propagator) RoundTrip(req *http.Request) (*http.Response, error) {
+	inject, ok := req.Context().Value(requestKey{}).(*requested)
+	if !ok {
+		return p.base.RoundTrip(req)
+	}
+	// RoundTrip não pode alterar a requisição recebida
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, inject.raw)
+	return p.base.RoundTrip(req)
+}
+
+// UnaryClientInterceptor aplica a falha do upstream às chamadas gRPC e repassa as
+// regras do header em metadata
+func (i *Injector) UnaryClientInterceptor(upstream string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if i == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		if _, err := i.apply(ctx, upstream); err != nil {
+			return err
+		}
+		if inject, ok := ctx.Value(requestKey{}).(*requested); ok {
+			ctx = metadata.AppendToOutgoingContext(ctx, metadataKey, inject.raw)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// rule falha do alvo: a do header da requisição ou, sem ela, a da configuração
+func (i *Injector) rule(ctx context.Context, target string) (Fault, bool) {
+	if inject, ok := ctx.Value(requestKey{}).(*requested); ok {
+		if f, ok := inject.rules[target]; ok {
+			return f, true
+		}
+	}
+	f, ok := (*i.rules.Load())[target]
+	return f, ok
+}
+
+// apply espera o atraso e sorteia o erro da regra do alvo. Retorna ErrInjected
+// quando o erro é sorteado ou o erro do contexto se ele terminar durante o atraso.
+func (i *Injector) apply(ctx context.Context, target string) (Fault, error) {
+	f, ok := i.rule(ctx, target)
+	if !ok {
+		return f, nil
+	}
+	span := trace.SpanFromContext(ctx)
+
+	if f.Delay > 0 {
+		record(span, target, "delay", delayKey.Int64(f.Delay.Milliseconds()))
+		timer := time.NewTimer(f.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return f, ctx.Err()
+		}
+	}
+	if f.ErrorRate > 0 && rand.Float64() < f.ErrorRate {
+		record(span, target, "error", errorKey.Bool(true))
+		return f, fmt.Errorf("%w: %s", ErrInjected, target)
+	}
+	return f, nil
+}
+
+// record conta a falha e a marca no span: fault.injected, o alvo e o detalhe da
+// falha como atributos, e um evento por falha com o tipo
+func record(span trace.Span, target, kind string, detail attribute.KeyValue) {
+	faultsInjected.WithLabelValues(target, kind).Inc()
+	span.SetAttributes(injectedKey.Bool(true), targetKey.String(target), detail)
+	span.AddEvent("fault injected", trace.WithAttributes(targetKey.String(target), typeKey.String(kind), detail))
+}
+
+// writeError responde no mesmo formato de erro dos handlers
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
-	"strings"
 
+	"github.com/marfebr/otel-lab/service-a/internal/deadline"
 	"github.com/marfebr/otel-lab/service-a/internal/service"
+	"github.com/marfebr/otel-lab/service-a/internal/telemetry"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -43,14 +46,17 @@ func (h *CEPHandler) HandleCEPValidation(w http.ResponseWriter, r *http.Request)
 	// Decodificar request
 	var req CEPRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
 	// Validar CEP
-	if err := h.cepValidator.ValidateCEP(req.CEP); err != nil {
-		span.RecordError(err)
+	trackValidation := telemetry.TrackTiming(ctx, "validation")
+	err := h.cepValidator.ValidateCEP(req.CEP)
+	trackValidation()
+	if err != nil {
+		telemetry.RecordError(span, err)
 		h.sendErrorResponse(w, err.Error(), http.StatusUnprocessableEntity)
 		return
 	}
@@ -58,23 +64,39 @@ func (h *CEPHandler) HandleCEPValidation(w http.ResponseWriter, r *http.Request)
 	// Buscar dados de clima no Serviço B
 	weatherResp, err := h.weatherService.GetWeatherByCEP(ctx, req.CEP)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		log.Printf("Erro retornado por GetWeatherByCEP: %v", err)
-		// Verificar se é erro do Serviço B e propagar status code
-		if err.Error() == "service B error: invalid zipcode" {
-			h.sendErrorResponse(w, "invalid zipcode", http.StatusUnprocessableEntity)
+		// Prazo da requisição esgotado, aqui ou no Serviço B
+		if errors.Is(err, context.DeadlineExceeded) {
+			h.sendErrorResponse(w, deadline.ErrExceeded.Error(), http.StatusGatewayTimeout)
 			return
 		}
-		if err.Error() == "service B error: can not find zipcode" || strings.Contains(err.Error(), "can not find zipcode") {
-			h.sendErrorResponse(w, "can not find zipcode", http.StatusNotFound)
-			return
+		// Erros respondidos pelo Serviço B: propagar o status code e a espera sugerida
+		var statusErr *service.StatusError
+		if errors.As(err, &statusErr) {
+			switch statusErr.StatusCode {
+			case http.StatusUnprocessableEntity, http.StatusNotFound:
+				h.sendErrorResponse(w, statusErr.Message, statusErr.StatusCode)
+				return
+			case http.StatusServiceUnavailable:
+				// Cota, limite de concorrência ou sobrecarga no Serviço B: o cliente
+				// pode tentar de novo após o Retry-After
+				if statusErr.RetryAfter != "" {
+					w.Header().Set("Retry-After", statusErr.RetryAfter)
+				}
+				h.sendErrorResponse(w, statusErr.Message, http.StatusServiceUnavailable)
+				return
+			}
 		}
 		// Erro interno do servidor
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Retornar dados de clima
+	// Retornar dados de clima (repassando a origem informada pelo Serviço B)
+	if weatherResp.Source != "" {
+		w.Header().Set(service.WeatherSourceHeader, weatherResp.Source)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(weatherResp)
@@ -0,0 +1,153 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Status possíveis de um serviço ou dependência
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check verificação de uma dependência
+type Check struct {
+	Name string
+	// Run retorna erro se a dependência não estiver pronta; o detalhe
+	// (opcional) é incluído no relatório mesmo em caso de sucesso
+	Run func(ctx context.Context) (detail string, err error)
+}
+
+// DependencyStatus resultado da verificação de uma dependência
+type DependencyStatus struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Detail    string  `json:"detail,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report relatório de prontidão com o status de cada dependência
+type Report struct {
+	Status       string             `json:"status"`
+	CheckedAt    time.Time          `json:"checked_at"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// Checker executa as verificações de prontidão e mantém o último resultado em cache
+type Checker struct {
+	checks  []Check
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu      sync.Mutex
+	report  *Report
+	expires time.Time
+}
+
+// NewChecker cria um Checker; ttl define por quanto tempo o resultado é reaproveitado
+// e timeout o limite de cada rodada de verificações
+func NewChecker(ttl, timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{
+		checks:  checks,
+		ttl:     ttl,
+		timeout: timeout,
+	}
+}
+
+// SetTTL altera o tempo de cache dos próximos relatórios
+func (c *Checker) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expires = c.expires.Add(ttl - c.ttl)
+	c.ttl = ttl
+}
+
+// Report retorna o relatório em cache ou executa as verificações em paralelo
+func (c *Checker) Report(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.report != nil && time.Now().Before(c.expires) {
+		return *c.report
+	}
+
+	// O resultado fica em cache, então não depende do cancelamento da requisição que o disparou
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+	defer cancel()
+
+	report := Report{
+		Status:       StatusOK,
+		CheckedAt:    time.Now(),
+		Dependencies: make([]DependencyStatus, len(c.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			report.Dependencies[i] = run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, dependency := range report.Dependencies {
+		if dependency.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+
+	c.report = &report
+	c.expires = time.Now().Add(c.ttl)
+
+	return report
+}
+
+// run executa uma verificação medindo sua latência
+func run(ctx context.Context, check Check) DependencyStatus {
+	start := time.Now()
+	detail, err := check.Run(ctx)
+
+	status := DependencyStatus{
+		Name:      check.Name,
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start)) / float64(time.Millisecond),
+		Detail:    detail,
+	}
+	if err != nil {
+		status.Status = StatusFail
+		status.Error = err.Error()
+	}
+
+	return status
+}
+
+// HandleLiveness responde 200 enquanto o processo estiver de pé
+func (c *Checker) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+// HandleReadiness responde 200 se todas as dependências estiverem prontas, senão 503
+func (c *Checker) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	report := c.Report(r.Context())
+
+	statusCode := http.StatusOK
+	if report.Status != StatusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, statusCode, report)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
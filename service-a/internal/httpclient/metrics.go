@@ -0,0 +1,39 @@
+package httpclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// openConns conexões abertas por upstream (em uso e ociosas)
+	openConns = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_client_open_connections",
+		Help: "Open outbound connections by upstream, in use or idle in the pool.",
+	}, []string{"upstream"})
+
+	// inFlight requisições aguardando resposta por upstream
+	inFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_client_in_flight_requests",
+		Help: "Outbound requests waiting for response headers by upstream.",
+	}, []string{"upstream"})
+
+	// acquisitions conexões obtidas do pool, novas ou reaproveitadas
+	acquisitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_connection_acquisitions_total",
+		Help: "Connections obtained for outbound requests by upstream and whether they were reused from the pool.",
+	}, []string{"upstream", "reused"})
+
+	// connWait espera até obter uma conexão (inclui dial e handshake das novas)
+	connWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_client_connection_wait_seconds",
+		Help:    "Time waiting for a connection, including dial and TLS handshake of new ones, by upstream.",
+		Buckets: []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"upstream"})
+
+	// dialErrors falhas ao abrir conexões
+	dialErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_dial_errors_total",
+		Help: "Failed outbound connection attempts by upstream.",
+	}, []string{"upstream"})
+)
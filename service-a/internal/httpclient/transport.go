@@ -0,0 +1,112 @@
+// Package httpclient cria os transportes HTTP compartilhados das chamadas de saída,
+// um por upstream, com pool de conexões ajustado e métricas do pool
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Options ajustes do pool de conexões e limites de tempo de cada fase da chamada
+type Options struct {
+	// MaxIdleConns conexões ociosas mantidas no total
+	MaxIdleConns int
+	// MaxIdleConnsPerHost conexões ociosas mantidas por host
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost conexões simultâneas por host (0 não limita)
+	MaxConnsPerHost int
+	// IdleConnTimeout tempo até fechar uma conexão ociosa
+	IdleConnTimeout time.Duration
+	// DialTimeout limite para abrir a conexão TCP
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout limite do handshake TLS
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout limite entre o envio da requisição e os headers da resposta
+	ResponseHeaderTimeout time.Duration
+}
+
+// Transport transporte de um upstream; expõe as métricas do pool sob o rótulo upstream
+type Transport struct {
+	upstream string
+	base     *http.Transport
+}
+
+// NewTransport cria o transporte de um upstream. Deve ser criado uma única vez e
+// compartilhado entre as chamadas para que as conexões sejam reaproveitadas.
+// HTTP/2 é negociado via ALPN quando o upstream usa https e o suporta.
+func NewTransport(upstream string, opts Options, tlsConfig *tls.Config) *Transport {
+	dialer := &net.Dialer{
+		Timeout:   opts.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	t := &Transport{upstream: upstream}
+	t.base = &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           t.dialContext(dialer),
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          opts.MaxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       opts.MaxConnsPerHost,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		TLSHandshakeTimeout:   opts.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	return t
+}
+
+// RoundTrip executa a requisição registrando a espera e o reaproveitamento de conexão
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	inFlight.WithLabelValues(t.upstream).Inc()
+	defer inFlight.WithLabelValues(t.upstream).Dec()
+
+	var getConn time.Time
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) { getConn = time.Now() },
+		GotConn: func(info httptrace.GotConnInfo) {
+			acquisitions.WithLabelValues(t.upstream, strconv.FormatBool(info.Reused)).Inc()
+			if !getConn.IsZero() {
+				connWait.WithLabelValues(t.upstream).Observe(time.Since(getConn).Seconds())
+			}
+		},
+	}
+	ctx := httptrace.WithClientTrace(req.Context(), trace)
+	return t.base.RoundTrip(req.WithContext(ctx))
+}
+
+// CloseIdleConnections fecha as conexões ociosas do pool
+func (t *Transport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
+
+// dialContext abre conexões contando as abertas e as falhas
+func (t *Transport) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			dialErrors.WithLabelValues(t.upstream).Inc()
+			return nil, err
+		}
+		openConns.WithLabelValues(t.upstream).Inc()
+		return &countedConn{Conn: conn, upstream: t.upstream}, nil
+	}
+}
+
+// countedConn decrementa o total de conexões abertas ao ser fechada
+type countedConn struct {
+	net.Conn
+	upstream string
+	once     sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(func() { openConns.WithLabelValues(c.upstream).Dec() })
+	return c.Conn.Close()
+}
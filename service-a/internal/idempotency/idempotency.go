@@ -0,0 +1,315 @@
+// Package idempotency deduplica as repetições de uma requisição pelo header
+// Idempotency-Key: a primeira resposta de cada chave é guardada por um período e
+// reenviada nas repetições, e as repetições que chegam enquanto a original ainda
+// está em curso esperam pelo resultado dela em vez de executá-la de novo
+package idempotency
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-a/internal/auth"
+	"github.com/marfebr/otel-lab/service-a/internal/deadline"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header chave de idempotência enviada pelo cliente
+const Header = "Idempotency-Key"
+
+// ReplayedHeader presente nas respostas reenviadas a partir de uma requisição anterior
+const ReplayedHeader = "Idempotent-Replayed"
+
+// maxKeyLength tamanho máximo aceito para a chave
+const maxKeyLength = 255
+
+// maxBodyBytes tamanho máximo do corpo lido para calcular a impressão digital
+const maxBodyBytes = 64 << 10
+
+// Resultados de cada requisição com chave
+const (
+	resultStored   = "stored"
+	resultReplayed = "replayed"
+	resultWaited   = "waited"
+	resultConflict = "conflict"
+	resultInvalid  = "invalid"
+)
+
+// resultKey atributo do span de servidor com o resultado da deduplicação
+const resultKey = attribute.Key("idempotency.result")
+
+// idempotencyRequests contador de requisições com Idempotency-Key por resultado
+var idempotencyRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "idempotency_requests_total",
+	Help: "Requests carrying an Idempotency-Key by result (stored, replayed, waited, conflict, invalid).",
+}, []string{"result"})
+
+// idempotencyEvictions contador de chaves descartadas antes de expirar por falta de espaço
+var idempotencyEvictions = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "idempotency_evictions_total",
+	Help: "Idempotency-Key entries dropped before expiring because the cache was full.",
+})
+
+// response resposta guardada: status, headers definidos pelo handler e corpo
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// entry requisição de uma chave; done é fechado quando a original termina
+type entry struct {
+	fingerprint [sha256.Size]byte
+	done        chan struct{}
+	resp        *response
+	expires     time.Time
+
+	key  string
+	elem *list.Element
+}
+
+// Cache respostas por chave, em memória e locais à réplica
+type Cache struct {
+	ttl time.Duration
+	// maxKeys número máximo de chaves guardadas
+	maxKeys int
+
+	mu        sync.Mutex
+	entries   map[string]*entry
+	order     *list.List // entradas da mais antiga para a mais nova
+	lastSweep time.Time
+}
+
+// NewCache cria um Cache que guarda cada resposta por ttl e no máximo maxKeys chaves;
+// cheio, descarta a chave mais antiga
+func NewCache(ttl time.Duration, maxKeys int) *Cache {
+	return &Cache{ttl: ttl, maxKeys: maxKeys, entries: make(map[string]*entry), order: list.New(), lastSweep: time.Now()}
+}
+
+// Middleware deduplica as requisições que trazem Idempotency-Key. A chave vale por
+// cliente (chave de API autenticada ou IP) e para o mesmo corpo: reusá-la com outro
+// corpo recebe 422 e corpos acima de 64 KiB recebem 413. Respostas 5xx não são guardadas, para que a repetição seja
+// executada de novo, mas são entregues às repetições que esperavam por elas.
+func (c *Cache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		span := trace.SpanFromContext(r.Context())
+		if len(key) > maxKeyLength {
+			record(span, resultInvalid)
+			writeError(w, "invalid Idempotency-Key (up to 255 characters)", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			record(span, resultInvalid)
+			writeError(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		case err != nil:
+			writeError(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := sha256.Sum256(body)
+		scoped := scope(r) + "|" + key
+
+		for {
+			e, owner := c.begin(scoped, fingerprint)
+			if e.fingerprint != fingerprint {
+				record(span, resultConflict)
+				writeError(w, "Idempotency-Key reused with a different request body", http.StatusUnprocessableEntity)
+				return
+			}
+			if owner {
+				record(span, resultStored)
+				c.execute(e, next, w, r)
+				return
+			}
+
+			result := resultReplayed
+			select {
+			case <-e.done:
+			default:
+				// Original ainda em curso: esperar pelo resultado dela
+				result = resultWaited
+				span.AddEvent("waiting for in-flight request")
+				select {
+				case <-e.done:
+				case <-r.Context().Done():
+					writeError(w, deadline.ErrExceeded.Error(), http.StatusGatewayTimeout)
+					return
+				}
+			}
+			if e.resp != nil {
+				record(span, result)
+				replay(w, e.resp)
+				return
+			}
+			// A original terminou sem resposta (panic): tentar executar de novo
+		}
+	})
+}
+
+// begin retorna a entrada da chave, criando-a se não houver uma válida; owner indica
+// que a requisição atual é a original e deve executar o handler
+func (c *Cache) begin(key string, fingerprint [sha256.Size]byte) (e *entry, owner bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.sweep(now)
+
+	if e, ok := c.entries[key]; ok {
+		if e.resp == nil || now.Before(e.expires) {
+			return e, false
+		}
+		c.remove(e)
+	}
+	if len(c.entries) >= c.maxKeys {
+		// Cheio: descartar a mais antiga, mesmo em curso; quem já espera por ela ainda
+		// recebe o resultado, apenas as próximas repetições executam de novo
+		c.remove(c.order.Front().Value.(*entry))
+		idempotencyEvictions.Inc()
+	}
+	e = &entry{fingerprint: fingerprint, done: make(chan struct{}), key: key}
+	e.elem = c.order.PushBack(e)
+	c.entries[key] = e
+	return e, true
+}
+
+// remove descarta a entrada, se ela ainda for a da chave
+func (c *Cache) remove(e *entry) {
+	if c.entries[e.key] == e {
+		delete(c.entries, e.key)
+	}
+	c.order.Remove(e.elem)
+}
+
+// execute chama o handler gravando a resposta e a publica para as repetições
+func (c *Cache) execute(e *entry, next http.Handler, w http.ResponseWriter, r *http.Request) {
+	rec := &recorder{ResponseWriter: w, before: keys(w.Header())}
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if rec.status != 0 {
+			e.resp = &response{status: rec.status, header: rec.header, body: rec.body.Bytes()}
+			e.expires = time.Now().Add(c.ttl)
+		}
+		if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+			c.remove(e)
+		}
+		close(e.done)
+	}()
+	next.ServeHTTP(rec, r)
+}
+
+// sweep descarta, no máximo uma vez por minuto, as respostas expiradas
+func (c *Cache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < time.Minute {
+		return
+	}
+	c.lastSweep = now
+
+	for _, e := range c.entries {
+		if e.resp != nil && now.After(e.expires) {
+			c.remove(e)
+		}
+	}
+}
+
+// scope cliente dono da chave: a chave de API autenticada ou, sem ela, o IP
+// definido pelo middleware.RealIP
+func scope(r *http.Request) string {
+	if key, ok := auth.KeyFromContext(r.Context()); ok {
+		return "key:" + key.ID
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// recorder repassa a resposta ao cliente e guarda uma cópia com os headers que o
+// handler definiu (os dos middlewares externos são recalculados a cada requisição)
+type recorder struct {
+	http.ResponseWriter
+	before map[string]bool
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(statusCode int) {
+	if rec.status == 0 {
+		rec.status = statusCode
+		rec.header = make(http.Header)
+		for name, values := range rec.Header() {
+			if !rec.before[name] {
+				rec.header[name] = append([]string(nil), values...)
+			}
+		}
+	}
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap permite ao http.ResponseController acessar o writer original
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// replay reenvia a resposta guardada
+func replay(w http.ResponseWriter, resp *response) {
+	for name, values := range resp.header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(resp.status)
+	w.Write(resp.body)
+}
+
+// keys nomes dos headers já definidos
+func keys(h http.Header) map[string]bool {
+	names := make(map[string]bool, len(h))
+	for name := range h {
+		names[name] = true
+	}
+	return names
+}
+
+// record conta o resultado e o registra no span de servidor
+func record(span trace.Span, result string) {
+	idempotencyRequests.WithLabelValues(result).Inc()
+	span.SetAttributes(resultKey.String(result))
+}
+
+// writeError responde no mesmo formato de erro dos handlers
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
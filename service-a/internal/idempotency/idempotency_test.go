@@ -0,0 +1,192 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// request requisição de teste ao middleware
+type request struct {
+	key  string
+	body string
+	addr string
+}
+
+func (r request) build() *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/cep", strings.NewReader(r.body))
+	if r.key != "" {
+		req.Header.Set(Header, r.key)
+	}
+	req.RemoteAddr = "10.0.0.1:1234"
+	if r.addr != "" {
+		req.RemoteAddr = r.addr
+	}
+	return req
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		maxKeys  int
+		requests []request
+		// Resultado esperado da última requisição
+		wantCalls    int32
+		wantStatus   int
+		wantReplayed bool
+	}{
+		{
+			name:       "without key",
+			status:     http.StatusOK,
+			requests:   []request{{body: "a"}, {body: "a"}},
+			wantCalls:  2,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "replayed",
+			status:       http.StatusOK,
+			requests:     []request{{key: "k1", body: "a"}, {key: "k1", body: "a"}},
+			wantCalls:    1,
+			wantStatus:   http.StatusOK,
+			wantReplayed: true,
+		},
+		{
+			name:         "client error replayed",
+			status:       http.StatusNotFound,
+			requests:     []request{{key: "k1", body: "a"}, {key: "k1", body: "a"}},
+			wantCalls:    1,
+			wantStatus:   http.StatusNotFound,
+			wantReplayed: true,
+		},
+		{
+			name:       "server error not stored",
+			status:     http.StatusInternalServerError,
+			requests:   []request{{key: "k1", body: "a"}, {key: "k1", body: "a"}},
+			wantCalls:  2,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "key reused with another body",
+			status:     http.StatusOK,
+			requests:   []request{{key: "k1", body: "a"}, {key: "k1", body: "b"}},
+			wantCalls:  1,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "same key from another client",
+			status:     http.StatusOK,
+			requests:   []request{{key: "k1", body: "a"}, {key: "k1", body: "a", addr: "10.0.0.2:1234"}},
+			wantCalls:  2,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "key too long",
+			status:     http.StatusOK,
+			requests:   []request{{key: strings.Repeat("k", maxKeyLength+1), body: "a"}},
+			wantCalls:  0,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "body too large",
+			status:     http.StatusOK,
+			requests:   []request{{key: "k1", body: strings.Repeat("a", maxBodyBytes+1)}},
+			wantCalls:  0,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "oldest key evicted when full",
+			status:     http.StatusOK,
+			maxKeys:    1,
+			requests:   []request{{key: "k1", body: "a"}, {key: "k2", body: "a"}, {key: "k1", body: "a"}},
+			wantCalls:  3,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "newest key kept when full",
+			status:       http.StatusOK,
+			maxKeys:      1,
+			requests:     []request{{key: "k1", body: "a"}, {key: "k2", body: "a"}, {key: "k2", body: "a"}},
+			wantCalls:    2,
+			wantStatus:   http.StatusOK,
+			wantReplayed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.maxKeys == 0 {
+				tt.maxKeys = 100
+			}
+			var calls atomic.Int32
+			handler := NewCache(time.Hour, tt.maxKeys).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.Header().Set("X-Weather-Source", "mock")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"city":"São Paulo"}`))
+			}))
+
+			var rec *httptest.ResponseRecorder
+			for _, req := range tt.requests {
+				rec = httptest.NewRecorder()
+				handler.ServeHTTP(rec, req.build())
+			}
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", got, tt.wantCalls)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if replayed := rec.Header().Get(ReplayedHeader) == "true"; replayed != tt.wantReplayed {
+				t.Errorf("%s = %v, want %v", ReplayedHeader, replayed, tt.wantReplayed)
+			}
+			if tt.wantReplayed {
+				if got := rec.Header().Get("X-Weather-Source"); got != "mock" {
+					t.Errorf("replayed X-Weather-Source = %q, want %q", got, "mock")
+				}
+				if got := rec.Body.String(); got != `{"city":"São Paulo"}` {
+					t.Errorf("replayed body = %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareWaitsForInFlight(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	handler := NewCache(time.Hour, 100).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		close(started)
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := request{key: "k1", body: "a"}
+	go handler.ServeHTTP(httptest.NewRecorder(), req.build())
+	<-started
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req.build())
+		done <- rec
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("retry answered before the original request finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(unblock)
+
+	rec := <-done
+	if calls.Load() != 1 || rec.Code != http.StatusOK || rec.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("calls = %d, status = %d, %s = %q; want 1, 200 and true", calls.Load(), rec.Code, ReplayedHeader, rec.Header().Get(ReplayedHeader))
+	}
+}
@@ -0,0 +1,41 @@
+package peerauth
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTransport adiciona à base o token assinado em cada requisição (hmac); no modo
+// mtls a base deve usar ClientTLSConfig. Respostas 401 são contadas como rejeição e
+// marcadas no span de cliente.
+func NewTransport(cfg Config, base http.RoundTripper) http.RoundTripper {
+	if cfg.Mode == ModeNone {
+		return base
+	}
+	return &clientTransport{base: base, cfg: cfg}
+}
+
+type clientTransport struct {
+	base http.RoundTripper
+	cfg  Config
+}
+
+func (t *clientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cfg.Mode == ModeHMAC {
+		req = req.Clone(req.Context())
+		req.Header.Set(TokenHeader, SignToken(t.cfg.Secret, t.cfg.ID, req.Method, req.URL.Path, time.Now().Add(t.cfg.TokenTTL)))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		rejections.WithLabelValues(t.cfg.Mode, ReasonRejectedByPeer).Inc()
+		trace.SpanFromContext(req.Context()).SetAttributes(
+			attribute.Bool(RejectedAttribute, true),
+			attribute.String(ReasonAttribute, ReasonRejectedByPeer),
+		)
+	}
+	return resp, err
+}
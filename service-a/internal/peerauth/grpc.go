@@ -0,0 +1,75 @@
+package peerauth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcMethod método usado na assinatura dos tokens das chamadas gRPC (o caminho é o
+// nome completo do método, ex.: /weather.v1.WeatherService/GetWeatherByCEP)
+const grpcMethod = "GRPC"
+
+// tokenMetadata chave de metadata com o token assinado do modo hmac
+var tokenMetadata = strings.ToLower(TokenHeader)
+
+// UnaryClientInterceptor assina as chamadas gRPC no modo hmac e conta as rejeições
+func UnaryClientInterceptor(cfg Config) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(signRPC(ctx, cfg, method), method, req, reply, cc, opts...)
+		recordPeerRejection(ctx, cfg, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor assina as chamadas gRPC com streaming no modo hmac
+func StreamClientInterceptor(cfg Config) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(signRPC(ctx, cfg, method), desc, cc, method, opts...)
+		if err != nil {
+			recordPeerRejection(ctx, cfg, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: stream, ctx: ctx, cfg: cfg}, nil
+	}
+}
+
+// clientStream conta a rejeição recebida na leitura das respostas do stream
+type clientStream struct {
+	grpc.ClientStream
+	ctx context.Context
+	cfg Config
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	recordPeerRejection(s.ctx, s.cfg, err)
+	return err
+}
+
+func signRPC(ctx context.Context, cfg Config, method string) context.Context {
+	if cfg.Mode != ModeHMAC {
+		return ctx
+	}
+	token := SignToken(cfg.Secret, cfg.ID, grpcMethod, method, time.Now().Add(cfg.TokenTTL))
+	return metadata.AppendToOutgoingContext(ctx, tokenMetadata, token)
+}
+
+// recordPeerRejection conta a recusa das credenciais pelo serviço chamado
+func recordPeerRejection(ctx context.Context, cfg Config, err error) {
+	if cfg.Mode == ModeNone || status.Code(err) != codes.Unauthenticated {
+		return
+	}
+	rejections.WithLabelValues(cfg.Mode, ReasonRejectedByPeer).Inc()
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Bool(RejectedAttribute, true),
+		attribute.String(ReasonAttribute, ReasonRejectedByPeer),
+	)
+}
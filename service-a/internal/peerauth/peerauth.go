@@ -0,0 +1,51 @@
+package peerauth
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Modos de autenticação entre serviços
+const (
+	ModeNone = "none"
+	ModeMTLS = "mtls"
+	ModeHMAC = "hmac"
+)
+
+// TokenHeader header com o token assinado do modo hmac
+const TokenHeader = "X-Service-Token"
+
+// Atributos de span da autenticação entre serviços
+const (
+	RejectedAttribute = "service_auth.rejected"
+	ReasonAttribute   = "service_auth.reason"
+)
+
+// ReasonRejectedByPeer motivo (label reason da métrica) das chamadas recusadas pelo
+// serviço chamado
+const ReasonRejectedByPeer = "rejected_by_peer"
+
+// Config configuração da autenticação entre serviços
+type Config struct {
+	// Mode none, mtls (certificado de cliente) ou hmac (token assinado)
+	Mode string
+	// ID identidade do serviço nos tokens emitidos
+	ID string
+	// Secret segredo compartilhado do modo hmac
+	Secret string
+	// TokenTTL validade dos tokens emitidos
+	TokenTTL time.Duration
+	// CAFile, CertFile e KeyFile certificados do modo mtls
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	MinVersion uint16
+}
+
+// rejections chamadas recusadas pela autenticação entre serviços
+var rejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "service_auth_rejections_total",
+	Help: "Service-to-service calls rejected by authentication, by mode and reason.",
+}, []string{"mode", "reason"})
@@ -0,0 +1,18 @@
+package peerauth
+
+import (
+	"crypto/tls"
+
+	"github.com/marfebr/otel-lab/service-a/internal/tlsconfig"
+)
+
+// ClientTLSConfig configuração TLS do cliente no modo mtls: confia apenas na CA
+// informada e apresenta o certificado do serviço
+func ClientTLSConfig(cfg Config) (*tls.Config, error) {
+	return tlsconfig.ClientConfig(tlsconfig.ClientOptions{
+		CAFile:     cfg.CAFile,
+		CertFile:   cfg.CertFile,
+		KeyFile:    cfg.KeyFile,
+		MinVersion: cfg.MinVersion,
+	})
+}
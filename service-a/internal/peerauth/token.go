@@ -0,0 +1,31 @@
+package peerauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// validPeerID IDs seguros para o token e para atributos de span
+var validPeerID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ValidID informa se o ID pode ser usado como emissor de tokens
+func ValidID(id string) bool {
+	return validPeerID.MatchString(id)
+}
+
+// SignToken gera o token "<id>.<expiração unix>.<assinatura>", válido apenas para
+// o método e o caminho informados
+func SignToken(secret, id, method, path string, expires time.Time) string {
+	payload := id + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + signature(secret, payload, method, path)
+}
+
+func signature(secret, payload, method, path string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload + "\n" + method + "\n" + path))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
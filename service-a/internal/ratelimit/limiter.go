@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-a/internal/auth"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Limit token bucket: Rate fichas por segundo, acumulando no máximo Burst
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Limits limite padrão e limites específicos por ID de chave de API
+type Limits struct {
+	Default Limit
+	PerKey  map[string]Limit
+}
+
+// Result resultado do consumo de uma ficha
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// Reset tempo até o bucket voltar a ficar cheio
+	Reset time.Duration
+	// RetryAfter tempo até a próxima ficha quando a requisição é negada
+	RetryAfter time.Duration
+}
+
+// Store guarda os buckets; Take consome uma ficha do bucket da chave
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// newResult calcula o resultado a partir das fichas restantes após o consumo
+func newResult(limit Limit, tokens float64, allowed bool) Result {
+	res := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// rateLimitRequests contador de decisões do limitador
+var rateLimitRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rate_limit_requests_total",
+	Help: "Requests evaluated by the rate limiter by result (allowed, limited, error).",
+}, []string{"result"})
+
+// Limiter aplica os limites por cliente: a chave de API autenticada (deve rodar
+// depois do auth.Authenticator) ou, sem ela, o IP definido pelo middleware.RealIP
+type Limiter struct {
+	store  Store
+	limits atomic.Pointer[Limits]
+}
+
+// NewLimiter cria um Limiter sobre o store informado
+func NewLimiter(store Store, limits Limits) *Limiter {
+	l := &Limiter{store: store}
+	l.SetLimits(limits)
+	return l
+}
+
+// SetLimits troca os limites em uso (recarga de configuração)
+func (l *Limiter) SetLimits(limits Limits) {
+	l.limits.Store(&limits)
+}
+
+// Middleware responde 429 quando o bucket do cliente está vazio e inclui os
+// headers RateLimit-* em todas as respostas. Falhas do store liberam a requisição.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits := l.limits.Load()
+		client, keyID := clientKey(r)
+
+		limit := limits.Default
+		if perKey, ok := limits.PerKey[keyID]; ok && keyID != "" {
+			limit = perKey
+		}
+
+		res, err := l.store.Take(r.Context(), client, limit)
+		if err != nil {
+			rateLimitRequests.WithLabelValues("error").Inc()
+			slog.WarnContext(r.Context(), "rate limiter store failed, allowing request", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("RateLimit-Policy", strconv.Itoa(limit.Burst)+";w="+ceilSeconds(seconds(float64(limit.Burst)/limit.Rate)))
+
+		if res.Allowed {
+			h.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+			rateLimitRequests.WithLabelValues("allowed").Inc()
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rateLimitRequests.WithLabelValues("limited").Inc()
+		span := trace.SpanFromContext(r.Context())
+		span.SetAttributes(attribute.Bool("ratelimit.limited", true))
+		span.AddEvent("rate limited", trace.WithAttributes(attribute.String("ratelimit.client", strings.SplitN(client, ":", 2)[0])))
+
+		h.Set("RateLimit-Reset", ceilSeconds(res.RetryAfter))
+		h.Set("Retry-After", ceilSeconds(res.RetryAfter))
+		h.Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]string{"error": "rate limit exceeded"})
+	})
+}
+
+// clientKey identifica o cliente pelo ID da chave autenticada pelo auth.Authenticator
+// ou, sem autenticação, pelo IP. A chave informada no header não é usada sem ter
+// sido validada, para que chaves inventadas não ganhem um bucket novo cada.
+func clientKey(r *http.Request) (client, keyID string) {
+	if key, ok := auth.KeyFromContext(r.Context()); ok {
+		return "key:" + key.ID, key.ID
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host, ""
+}
+
+// ceilSeconds formata a duração em segundos inteiros, arredondando para cima
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
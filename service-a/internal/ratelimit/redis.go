@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript token bucket atômico no Redis; usa o relógio do Redis para que todas
+// as réplicas concordem sobre o reabastecimento
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore buckets compartilhados entre réplicas no Redis
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore cria um RedisStore a partir de uma URL redis://[:senha@]host:porta/db
+func NewRedisStore(rawURL, prefix string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	return &RedisStore{client: redis.NewClient(opts), prefix: prefix}, nil
+}
+
+// Take consome uma ficha do bucket da chave
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	values, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("redis rate limit script: %w", err)
+	}
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("redis rate limit script: unexpected reply %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	tokensStr, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("redis rate limit script: invalid tokens %q", tokensStr)
+	}
+
+	return newResult(limit, tokens, allowed == 1), nil
+}
+
+// Close encerra as conexões com o Redis
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
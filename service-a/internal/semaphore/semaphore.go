@@ -0,0 +1,131 @@
+// Package semaphore limita as chamadas simultâneas com uma fila de espera limitada,
+// atendida em ordem de chegada. É a base do controle de admissão (shed) e dos
+// bulkheads, que acrescentam as prioridades, as métricas e os erros de cada um.
+package semaphore
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueFull a fila de espera está cheia
+	ErrQueueFull = errors.New("queue full")
+	// ErrQueueTimeout a espera na fila passou do limite
+	ErrQueueTimeout = errors.New("queue timeout")
+)
+
+// Semaphore vagas em uso e fila de espera
+type Semaphore struct {
+	maxQueue int
+	// onChange recebe as vagas em uso e o tamanho da fila a cada mudança (métricas)
+	onChange func(inFlight, queued int)
+
+	mu       sync.Mutex
+	limit    int
+	inFlight int
+	queue    []chan struct{}
+}
+
+// New cria o Semaphore com limit vagas e até maxQueue chamadas na fila. onChange,
+// se não for nil, é chamado com o Semaphore travado e não deve chamá-lo.
+func New(limit, maxQueue int, onChange func(inFlight, queued int)) *Semaphore {
+	return &Semaphore{limit: limit, maxQueue: maxQueue, onChange: onChange}
+}
+
+// TryAcquire reserva uma vaga sem esperar, apenas se houver menos de limit vagas
+// em uso (limit permite reservar só parte delas) e ninguém na fila
+func (s *Semaphore) TryAcquire(limit int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) > 0 || s.inFlight >= min(limit, s.limit) {
+		return false
+	}
+	s.inFlight++
+	s.changed()
+	return true
+}
+
+// Acquire reserva uma vaga, esperando na fila até timeout se todas estão em uso;
+// queued indica se a chamada passou pela fila. Sem vaga, retorna ErrQueueFull,
+// ErrQueueTimeout ou o erro do contexto. A vaga é devolvida com Release.
+func (s *Semaphore) Acquire(ctx context.Context, timeout time.Duration) (queued bool, err error) {
+	s.mu.Lock()
+	if len(s.queue) == 0 && s.inFlight < s.limit {
+		s.inFlight++
+		s.changed()
+		s.mu.Unlock()
+		return false, nil
+	}
+	if len(s.queue) >= s.maxQueue {
+		s.mu.Unlock()
+		return false, ErrQueueFull
+	}
+	ready := make(chan struct{})
+	s.queue = append(s.queue, ready)
+	s.changed()
+	s.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return true, nil
+	case <-timer.C:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := slices.Index(s.queue, ready); i >= 0 {
+		s.queue = slices.Delete(s.queue, i, i+1)
+		s.changed()
+		return true, err
+	}
+	// A vaga foi concedida enquanto desistíamos: devolvê-la ao próximo da fila
+	s.inFlight--
+	s.grant()
+	return true, err
+}
+
+// Release devolve uma vaga, concedendo-a ao primeiro da fila
+func (s *Semaphore) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inFlight--
+	s.grant()
+}
+
+// SetLimit altera o número de vagas; as que sobrarem são concedidas à fila
+func (s *Semaphore) SetLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.limit = limit
+	s.grant()
+}
+
+// grant concede as vagas livres aos primeiros da fila; chamado com mu travado
+func (s *Semaphore) grant() {
+	for len(s.queue) > 0 && s.inFlight < s.limit {
+		close(s.queue[0])
+		s.queue = s.queue[1:]
+		s.inFlight++
+	}
+	s.changed()
+}
+
+// changed publica as vagas em uso e a fila; chamado com mu travado
+func (s *Semaphore) changed() {
+	if s.onChange != nil {
+		s.onChange(s.inFlight, len(s.queue))
+	}
+}
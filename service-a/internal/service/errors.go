@@ -1,8 +1,24 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Erros específicos do serviço
 var (
 	ErrInvalidCEP = errors.New("invalid zipcode")
 )
+
+// StatusError erro respondido pelo Serviço B, com o status HTTP equivalente (no
+// transporte gRPC, traduzido do código) e a espera sugerida, se houver
+type StatusError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter valor do header Retry-After (ou do trailer retry-after), em segundos
+	RetryAfter string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("service B error: %s", e.Message)
+}
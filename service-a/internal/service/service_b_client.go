@@ -8,26 +8,45 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
+	"github.com/marfebr/otel-lab/service-a/internal/auth"
+	"github.com/marfebr/otel-lab/service-a/internal/deadline"
+	"github.com/marfebr/otel-lab/service-a/internal/fault"
+	"github.com/marfebr/otel-lab/service-a/internal/telemetry"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// serviceBTransport transporte das chamadas ao Serviço B (HTTP/JSON ou gRPC)
+type serviceBTransport interface {
+	weatherByCEP(ctx context.Context, cep string) (*WeatherResponse, error)
+	ping(ctx context.Context) error
+}
+
 // ServiceBClient cliente para comunicação com Serviço B
 type ServiceBClient struct {
-	baseURL string
-	client  *http.Client
-	tracer  trace.Tracer
+	transport serviceBTransport
+	tracer    trace.Tracer
 }
 
-// NewServiceBClient cria uma nova instância do cliente do Serviço B
-func NewServiceBClient(baseURL string, tracer trace.Tracer) *ServiceBClient {
+// NewServiceBClient cria uma nova instância do cliente HTTP do Serviço B; transport
+// (nil usa o padrão) aplica a autenticação entre serviços
+func NewServiceBClient(baseURL string, timeout time.Duration, tracer trace.Tracer, transport http.RoundTripper, faults *fault.Injector) *ServiceBClient {
 	return &ServiceBClient{
-		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
+		transport: &httpTransport{
+			baseURL: baseURL,
+			timeout: timeout,
+			// O prazo restante da requisição segue no header X-Request-Timeout e as
+			// regras de X-Fault-Inject seguem no header de mesmo nome
+			client: &http.Client{
+				Transport: telemetry.NewTransport(faults.Transport("service-b", deadline.NewTransport(faults.Propagate(transport)))),
+			},
+			// Verificações de saúde não são instrumentadas para não gerar um trace por probe
+			healthClient: &http.Client{
+				Timeout:   5 * time.Second,
+				Transport: transport,
+			},
 		},
 		tracer: tracer,
 	}
@@ -39,51 +58,88 @@ func (c *ServiceBClient) GetWeatherByCEP(ctx context.Context, cep string) (*Weat
 	defer span.End()
 
 	log.Printf("Enviando CEP para Service B: %s", cep)
+	auth.CountUpstreamCall(ctx)
+	return c.transport.weatherByCEP(ctx, cep)
+}
+
+// Ping verifica se o Serviço B está de pé
+func (c *ServiceBClient) Ping(ctx context.Context) error {
+	return c.transport.ping(ctx)
+}
+
+// httpTransport chama o POST /weather do Serviço B
+type httpTransport struct {
+	baseURL      string
+	timeout      time.Duration
+	client       *http.Client
+	healthClient *http.Client
+}
+
+func (t *httpTransport) weatherByCEP(ctx context.Context, cep string) (*WeatherResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
 	// Preparar request
 	requestBody := CEPRequest{CEP: cep}
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Criar requisição HTTP
-	url := fmt.Sprintf("%s/weather", c.baseURL)
+	url := fmt.Sprintf("%s/weather", t.baseURL)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Configurar headers
 	req.Header.Set("Content-Type", "application/json")
 
-	// Propagar contexto OTEL
-	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
-
-	// Executar requisição
-	resp, err := c.client.Do(req)
+	// Executar requisição (o transport instrumentado propaga o contexto OTEL)
+	trackCall := telemetry.TrackTiming(ctx, "service-b")
+	resp, err := t.client.Do(req)
 	if err != nil {
-		span.RecordError(err)
+		trackCall()
+		telemetry.RecordError(span, err)
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Ler resposta
 	body, err := io.ReadAll(resp.Body)
+	trackCall()
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	// Incorporar os tempos internos do Serviço B (cep-lookup, weather-lookup, total)
+	if recorder := telemetry.TimingFromContext(ctx); recorder != nil {
+		recorder.Merge("service-b-", telemetry.ParseServerTiming(resp.Header.Get(telemetry.ServerTimingHeader)))
+	}
+
 	// Verificar status code
 	if resp.StatusCode != http.StatusOK {
-		span.RecordError(fmt.Errorf("service B returned status %d", resp.StatusCode))
+		telemetry.RecordError(span, fmt.Errorf("service B returned status %d", resp.StatusCode))
+
+		// Prazo esgotado no Serviço B
+		if resp.StatusCode == http.StatusGatewayTimeout {
+			return nil, fmt.Errorf("service B error: %w", deadline.ErrExceeded)
+		}
 
 		// Tentar decodificar erro
 		var errorResp ErrorResponse
 		if json.Unmarshal(body, &errorResp) == nil {
-			return nil, fmt.Errorf("service B error: %s", errorResp.Error)
+			return nil, &StatusError{
+				StatusCode: resp.StatusCode,
+				Message:    errorResp.Error,
+				RetryAfter: resp.Header.Get("Retry-After"),
+			}
 		}
 
 		return nil, fmt.Errorf("service B returned status %d: %s", resp.StatusCode, string(body))
@@ -92,9 +148,48 @@ func (c *ServiceBClient) GetWeatherByCEP(ctx context.Context, cep string) (*Weat
 	// Decodificar resposta de sucesso
 	var weatherResp WeatherResponse
 	if err := json.Unmarshal(body, &weatherResp); err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	weatherResp.Source = resp.Header.Get(WeatherSourceHeader)
 
 	return &weatherResp, nil
 }
+
+// ping verifica o GET /readyz, que responde 503 quando uma dependência do Serviço B falha
+func (t *httpTransport) ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.baseURL+"/readyz", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := t.healthClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach service B: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// O relatório indica quais dependências do Serviço B falharam
+		var report struct {
+			Dependencies []struct {
+				Name   string `json:"name"`
+				Status string `json:"status"`
+			} `json:"dependencies"`
+		}
+		var failed []string
+		if json.NewDecoder(resp.Body).Decode(&report) == nil {
+			for _, dependency := range report.Dependencies {
+				if dependency.Status != "ok" {
+					failed = append(failed, dependency.Name)
+				}
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("service B is not ready: %s failing", strings.Join(failed, ", "))
+		}
+		return fmt.Errorf("service B returned status %d", resp.StatusCode)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
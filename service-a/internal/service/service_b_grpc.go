@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-a/internal/deadline"
+	"github.com/marfebr/otel-lab/service-a/internal/fault"
+	"github.com/marfebr/otel-lab/service-a/internal/peerauth"
+	"github.com/marfebr/otel-lab/service-a/internal/telemetry"
+	"github.com/marfebr/otel-lab/service-a/internal/weatherpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// NewServiceBGRPCClient cria o cliente do Serviço B que usa o WeatherService gRPC.
+// tlsConfig nil conecta sem TLS; serviceAuth assina as chamadas no modo hmac e
+// faults injeta as falhas do upstream service-b.
+func NewServiceBGRPCClient(addr string, timeout time.Duration, tracer trace.Tracer, tlsConfig *tls.Config, serviceAuth peerauth.Config, faults *fault.Injector) (*ServiceBClient, error) {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(creds),
+		// Verificações de saúde não são instrumentadas para não gerar um trace por probe
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
+		grpc.WithChainUnaryInterceptor(peerauth.UnaryClientInterceptor(serviceAuth), faults.UnaryClientInterceptor("service-b")),
+		grpc.WithChainStreamInterceptor(peerauth.StreamClientInterceptor(serviceAuth)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC connection to service B: %w", err)
+	}
+
+	return &ServiceBClient{
+		transport: &grpcTransport{
+			weather: weatherpb.NewWeatherServiceClient(conn),
+			health:  healthpb.NewHealthClient(conn),
+			timeout: timeout,
+		},
+		tracer: tracer,
+	}, nil
+}
+
+// grpcTransport chama o WeatherService do Serviço B
+type grpcTransport struct {
+	weather weatherpb.WeatherServiceClient
+	health  healthpb.HealthClient
+	timeout time.Duration
+}
+
+func (t *grpcTransport) weatherByCEP(ctx context.Context, cep string) (*WeatherResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	// O stats handler do otelgrpc cria o span de cliente e propaga o contexto OTEL
+	trackCall := telemetry.TrackTiming(ctx, "service-b")
+	var trailer metadata.MD
+	resp, err := t.weather.GetWeatherByCEP(ctx, &weatherpb.GetWeatherByCEPRequest{Cep: cep}, grpc.Trailer(&trailer))
+	trackCall()
+	if err != nil {
+		telemetry.RecordError(span, err)
+		return nil, grpcError(err, trailer)
+	}
+
+	return &WeatherResponse{
+		City:   resp.GetCity(),
+		TempC:  resp.GetTempC(),
+		TempF:  resp.GetTempF(),
+		TempK:  resp.GetTempK(),
+		Source: resp.GetSource(),
+	}, nil
+}
+
+// retryAfterMetadata trailer com a espera sugerida pelo Serviço B, em segundos
+const retryAfterMetadata = "retry-after"
+
+// grpcHTTPStatus status HTTP equivalente aos códigos que o Serviço B usa para erros
+// de negócio, de cota e de sobrecarga
+var grpcHTTPStatus = map[codes.Code]int{
+	codes.InvalidArgument:   http.StatusUnprocessableEntity,
+	codes.NotFound:          http.StatusNotFound,
+	codes.ResourceExhausted: http.StatusServiceUnavailable,
+	codes.Unavailable:       http.StatusServiceUnavailable,
+	codes.Unauthenticated:   http.StatusUnauthorized,
+}
+
+// grpcError traduz o status para os mesmos erros do transporte HTTP, que o handler
+// converte em status de resposta; a espera vem do trailer retry-after
+func grpcError(err error, trailer metadata.MD) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("failed to execute gRPC request: %w", err)
+	}
+	if st.Code() == codes.DeadlineExceeded {
+		return fmt.Errorf("service B error: %w", deadline.ErrExceeded)
+	}
+	statusCode, ok := grpcHTTPStatus[st.Code()]
+	if !ok {
+		return fmt.Errorf("service B returned gRPC status %s: %s", st.Code(), st.Message())
+	}
+
+	statusErr := &StatusError{StatusCode: statusCode, Message: st.Message()}
+	if values := trailer.Get(retryAfterMetadata); len(values) > 0 {
+		statusErr.RetryAfter = values[0]
+	}
+	return statusErr
+}
+
+func (t *grpcTransport) ping(ctx context.Context) error {
+	resp, err := t.health.Check(ctx, &healthpb.HealthCheckRequest{Service: weatherpb.WeatherService_ServiceDesc.ServiceName})
+	if err != nil {
+		return fmt.Errorf("failed to reach service B: %w", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("service B is %s", resp.GetStatus())
+	}
+	return nil
+}
@@ -1,5 +1,8 @@
 package service
 
+// WeatherSourceHeader header com a origem dos dados de clima (live, mock ou fixture)
+const WeatherSourceHeader = "X-Weather-Source"
+
 // CEPRequest representa o request para validação de CEP
 type CEPRequest struct {
 	CEP string `json:"cep"`
@@ -11,6 +14,8 @@ type WeatherResponse struct {
 	TempC float64 `json:"temp_C"`
 	TempF float64 `json:"temp_F"`
 	TempK float64 `json:"temp_K"`
+	// Source origem dos dados informada pelo Serviço B (header X-Weather-Source)
+	Source string `json:"-"`
 }
 
 // ErrorResponse representa uma resposta de erro
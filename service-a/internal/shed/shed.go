@@ -0,0 +1,207 @@
+// Package shed controla a admissão de requisições no servidor: limita as requisições
+// em curso, mantém uma fila curta com prazo e descarta cedo o que não cabe, por
+// ordem de prioridade, para que uma rajada não degrade todas as respostas
+package shed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-a/internal/semaphore"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Priority classe de prioridade de uma requisição
+type Priority int
+
+const (
+	// PriorityCritical sempre admitida (health checks e métricas)
+	PriorityCritical Priority = iota
+	// PriorityNormal admitida até o limite, com espera na fila
+	PriorityNormal
+	// PriorityLow admitida só com folga (LowPriorityShare do limite) e sem fila
+	PriorityLow
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityCritical:
+		return "critical"
+	case PriorityLow:
+		return "low"
+	default:
+		return "normal"
+	}
+}
+
+// Motivos de descarte
+const (
+	ReasonQueueFull    = "queue_full"
+	ReasonQueueTimeout = "queue_timeout"
+	ReasonLowPriority  = "low_priority"
+)
+
+// ErrOverloaded erro retornado quando a requisição é descartada
+var ErrOverloaded = errors.New("server overloaded")
+
+// ShedError detalha o descarte; errors.Is(err, ErrOverloaded) é verdadeiro
+type ShedError struct {
+	Priority Priority
+	Reason   string
+}
+
+func (e *ShedError) Error() string {
+	return fmt.Sprintf("server overloaded: %s request shed (%s)", e.Priority, e.Reason)
+}
+
+// Is permite comparar com ErrOverloaded
+func (e *ShedError) Is(target error) bool {
+	return target == ErrOverloaded
+}
+
+// Limits limites de admissão
+type Limits struct {
+	// MaxInFlight requisições atendidas ao mesmo tempo (sem contar as críticas)
+	MaxInFlight int
+	// MaxQueue requisições que podem esperar por admissão
+	MaxQueue int
+	// MaxQueueTime espera máxima na fila; acima disso a requisição é descartada
+	MaxQueueTime time.Duration
+	// LowPriorityShare fração de MaxInFlight que as requisições de baixa prioridade podem ocupar
+	LowPriorityShare float64
+}
+
+// Atributos dos eventos de span
+const (
+	priorityKey = attribute.Key("shed.priority")
+	reasonKey   = attribute.Key("shed.reason")
+	queuedKey   = attribute.Key("shed.queue_ms")
+)
+
+// retryAfter espera sugerida às requisições descartadas, em segundos
+const retryAfter = "1"
+
+var (
+	inFlightGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "admission_in_flight_requests",
+		Help: "Admitted requests being served, excluding critical ones.",
+	})
+
+	queuedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "admission_queued_requests",
+		Help: "Requests waiting for admission.",
+	})
+
+	shedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "admission_shed_requests_total",
+		Help: "Requests shed by admission control by priority and reason (queue_full, queue_timeout, low_priority).",
+	}, []string{"priority", "reason"})
+)
+
+// Limiter controle de admissão compartilhado por todas as rotas do servidor
+type Limiter struct {
+	limits Limits
+	sem    *semaphore.Semaphore
+	// lowLimit vagas que as requisições de baixa prioridade podem ocupar
+	lowLimit int
+}
+
+// NewLimiter cria o Limiter; MaxInFlight zero desativa o controle (retorna nil)
+func NewLimiter(limits Limits) *Limiter {
+	if limits.MaxInFlight <= 0 {
+		return nil
+	}
+	return &Limiter{
+		limits: limits,
+		sem: semaphore.New(limits.MaxInFlight, limits.MaxQueue, func(inFlight, queued int) {
+			inFlightGauge.Set(float64(inFlight))
+			queuedGauge.Set(float64(queued))
+		}),
+		lowLimit: int(math.Ceil(float64(limits.MaxInFlight) * limits.LowPriorityShare)),
+	}
+}
+
+// Admit admite a requisição ou a descarta com *ShedError. Críticas sempre entram; as
+// normais esperam na fila até MaxQueueTime; as de baixa prioridade só entram se houver
+// folga. A função retornada libera a vaga. Um Limiter nil admite tudo.
+func (l *Limiter) Admit(ctx context.Context, priority Priority) (func(), error) {
+	if l == nil || priority == PriorityCritical {
+		return func() {}, nil
+	}
+	span := trace.SpanFromContext(ctx)
+
+	if priority == PriorityLow {
+		if !l.sem.TryAcquire(l.lowLimit) {
+			return nil, shed(span, priority, ReasonLowPriority, 0)
+		}
+		return l.releaser(), nil
+	}
+
+	start := time.Now()
+	queued, err := l.sem.Acquire(ctx, l.limits.MaxQueueTime)
+	switch {
+	case errors.Is(err, semaphore.ErrQueueFull):
+		return nil, shed(span, priority, ReasonQueueFull, 0)
+	case errors.Is(err, semaphore.ErrQueueTimeout):
+		return nil, shed(span, priority, ReasonQueueTimeout, time.Since(start))
+	case err != nil:
+		return nil, err
+	}
+	if queued {
+		span.AddEvent("request admitted", trace.WithAttributes(
+			priorityKey.String(priority.String()),
+			queuedKey.Int64(time.Since(start).Milliseconds()),
+		))
+	}
+	return l.releaser(), nil
+}
+
+// Middleware aplica o controle de admissão com a prioridade dada por classify;
+// requisições descartadas recebem 503 com Retry-After
+func (l *Limiter) Middleware(classify func(*http.Request) Priority) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if l == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			release, err := l.Admit(r.Context(), classify(r))
+			if err != nil {
+				w.Header().Set("Retry-After", retryAfter)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				json.NewEncoder(w).Encode(map[string]string{"error": ErrOverloaded.Error()})
+				return
+			}
+			defer release()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// releaser libera a vaga uma única vez e admite o próximo da fila
+func (l *Limiter) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(l.sem.Release)
+	}
+}
+
+// shed conta o descarte e o registra como evento no span da requisição
+func shed(span trace.Span, priority Priority, reason string, queued time.Duration) error {
+	shedRequests.WithLabelValues(priority.String(), reason).Inc()
+	span.AddEvent("request shed", trace.WithAttributes(
+		priorityKey.String(priority.String()),
+		reasonKey.String(reason),
+		queuedKey.Int64(queued.Milliseconds()),
+	))
+	return &ShedError{Priority: priority, Reason: reason}
+}
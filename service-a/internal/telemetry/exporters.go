@@ -0,0 +1,164 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/marfebr/otel-lab/service-a/internal/tlsconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exportadores de spans suportados (OTEL_TRACES_EXPORTER)
+const (
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterZipkin   = "zipkin"
+	ExporterStdout   = "stdout"
+	ExporterFile     = "file"
+	ExporterNone     = "none"
+)
+
+// ValidateExporters verifica os nomes dos exportadores; none desativa a exportação
+// e não pode ser combinado com outros
+func ValidateExporters(names []string) error {
+	for _, name := range names {
+		switch name {
+		case ExporterOTLPGRPC, "otlp", ExporterOTLPHTTP, ExporterZipkin, ExporterStdout, ExporterFile:
+		case ExporterNone:
+			if len(names) > 1 {
+				return fmt.Errorf("exporter %q cannot be combined with other exporters", ExporterNone)
+			}
+		default:
+			return fmt.Errorf("unknown exporter %q", name)
+		}
+	}
+	return nil
+}
+
+// newSpanExporters cria os exportadores de spans selecionados na configuração.
+// Nenhum deles conecta no destino durante a criação, então a ausência do
+// collector não impede a inicialização do serviço. Se um falhar, os já criados
+// são encerrados.
+func newSpanExporters(ctx context.Context, cfg Config) ([]sdktrace.SpanExporter, error) {
+	if err := ValidateExporters(cfg.Exporters); err != nil {
+		return nil, err
+	}
+	if hasExporter(cfg.Exporters, ExporterNone) {
+		return nil, nil
+	}
+
+	var exporters []sdktrace.SpanExporter
+	for _, name := range cfg.Exporters {
+		exporter, err := newSpanExporter(ctx, name, cfg)
+		if err != nil {
+			for _, created := range exporters {
+				created.Shutdown(ctx)
+			}
+			return nil, fmt.Errorf("failed to create %s exporter: %w", name, err)
+		}
+		exporters = append(exporters, exporter)
+	}
+
+	return exporters, nil
+}
+
+// hasExporter verifica se o exportador está selecionado
+func hasExporter(names []string, exporter string) bool {
+	for _, name := range names {
+		if name == exporter {
+			return true
+		}
+	}
+	return false
+}
+
+// newSpanExporter cria um exportador de spans pelo nome
+func newSpanExporter(ctx context.Context, name string, cfg Config) (sdktrace.SpanExporter, error) {
+	switch name {
+	case ExporterOTLPGRPC, "otlp":
+		conn, err := newCollectorConn(cfg.OTLPGRPCEndpoint, cfg.OTLPTLS)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return collectorExporter{SpanExporter: exporter, conn: conn}, nil
+	case ExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPHTTPEndpoint)}
+		if cfg.OTLPTLS.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			tlsConfig, err := collectorTLSConfig(cfg.OTLPTLS)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterZipkin:
+		return zipkin.New(cfg.ZipkinEndpoint)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterFile:
+		return newFileSpanExporter(ctx, cfg.File)
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", name)
+	}
+}
+
+// collectorExporter fecha a conexão com o collector ao encerrar o exportador, já
+// que o otlptracegrpc não fecha conexões recebidas prontas
+type collectorExporter struct {
+	sdktrace.SpanExporter
+	conn *grpc.ClientConn
+}
+
+func (e collectorExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.SpanExporter.Shutdown(ctx), e.conn.Close())
+}
+
+// newCollectorConn cria a conexão gRPC com o collector, com ou sem TLS
+func newCollectorConn(endpoint string, cfg OTLPTLSConfig) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if !cfg.Insecure {
+		tlsConfig, err := collectorTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC connection to collector: %w", err)
+	}
+	return conn, nil
+}
+
+// collectorTLSConfig configuração TLS da conexão com o collector
+func collectorTLSConfig(cfg OTLPTLSConfig) (*tls.Config, error) {
+	tlsConfig, err := tlsconfig.ClientConfig(tlsconfig.ClientOptions{
+		CAFile:     cfg.CAFile,
+		CertFile:   cfg.CertFile,
+		KeyFile:    cfg.KeyFile,
+		ServerName: cfg.ServerName,
+		MinVersion: cfg.MinVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure collector TLS: %w", err)
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,149 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// Nomes dos arquivos gravados pelo exportador "file" dentro de FileConfig.Dir
+const (
+	TracesFileName  = "traces.jsonl"
+	MetricsFileName = "metrics.jsonl"
+)
+
+// rotatingFile arquivo de linhas JSON com rotação por tamanho
+// (path -> path.1 -> path.2 ... até maxBackups)
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// openRotatingFile abre (ou cria) o arquivo em modo append
+func openRotatingFile(path string, maxBytes int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+
+	f := &rotatingFile{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open abre o arquivo atual e carrega seu tamanho
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat %s: %w", f.path, err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// WriteLine grava uma linha, rotacionando o arquivo se o limite for excedido
+func (f *rotatingFile) WriteLine(line []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	if f.maxBytes > 0 && f.size > 0 && f.size+int64(len(line))+1 > f.maxBytes {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := f.file.Write(append(line, '\n'))
+	f.size += int64(n)
+	return err
+}
+
+// rotate desloca os backups existentes e reabre um arquivo vazio
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	if f.maxBackups > 0 {
+		for i := f.maxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+		}
+		if err := os.Rename(f.path, f.path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate %s: %w", f.path, err)
+		}
+	} else if err := os.Truncate(f.path, 0); err != nil {
+		return fmt.Errorf("failed to truncate %s: %w", f.path, err)
+	}
+
+	return f.open()
+}
+
+// Close fecha o arquivo
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// fileTraceClient cliente OTLP que grava cada lote de spans como uma linha
+// ExportTraceServiceRequest em OTLP/JSON
+type fileTraceClient struct {
+	cfg  FileConfig
+	file *rotatingFile
+}
+
+// newFileSpanExporter cria o exportador de spans para arquivo
+func newFileSpanExporter(ctx context.Context, cfg FileConfig) (sdktrace.SpanExporter, error) {
+	return otlptrace.New(ctx, &fileTraceClient{cfg: cfg})
+}
+
+// Start abre o arquivo de traces
+func (c *fileTraceClient) Start(context.Context) error {
+	file, err := openRotatingFile(filepath.Join(c.cfg.Dir, TracesFileName), c.cfg.MaxBytes, c.cfg.MaxBackups)
+	if err != nil {
+		return err
+	}
+	c.file = file
+	return nil
+}
+
+// Stop fecha o arquivo de traces
+func (c *fileTraceClient) Stop(context.Context) error {
+	return c.file.Close()
+}
+
+// UploadTraces grava o lote de spans
+func (c *fileTraceClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	line, err := marshalOTLPJSON(&coltracepb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	return c.file.WriteLine(line)
+}
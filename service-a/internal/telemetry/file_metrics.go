@@ -0,0 +1,252 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// fileMetricsWriter grava periodicamente um snapshot das métricas do Prometheus
+// como linhas ExportMetricsServiceRequest em OTLP/JSON
+type fileMetricsWriter struct {
+	gatherer    prometheus.Gatherer
+	file        *rotatingFile
+	resource    *resourcepb.Resource
+	startTime   time.Time
+	interval    time.Duration
+	stop        chan struct{}
+	done        chan struct{}
+	stopOnce    sync.Once
+	serviceName string
+}
+
+// startFileMetrics abre o arquivo de métricas e inicia a gravação periódica
+func startFileMetrics(cfg FileConfig, serviceName string, gatherer prometheus.Gatherer) (*fileMetricsWriter, error) {
+	file, err := openRotatingFile(filepath.Join(cfg.Dir, MetricsFileName), cfg.MaxBytes, cfg.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := cfg.MetricsInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	w := &fileMetricsWriter{
+		gatherer:    gatherer,
+		file:        file,
+		startTime:   time.Now(),
+		interval:    interval,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+		serviceName: serviceName,
+		resource: &resourcepb.Resource{
+			Attributes: []*commonpb.KeyValue{stringKV("service.name", serviceName)},
+		},
+	}
+	go w.run()
+
+	return w, nil
+}
+
+// run grava um snapshot a cada intervalo até o shutdown
+func (w *fileMetricsWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.write(); err != nil {
+				log.Printf("failed to write metrics file: %v", err)
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// write coleta as métricas e grava uma linha
+func (w *fileMetricsWriter) write() error {
+	families, err := w.gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	req := &colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricpb.ResourceMetrics{{
+			Resource: w.resource,
+			ScopeMetrics: []*metricpb.ScopeMetrics{{
+				Scope:   &commonpb.InstrumentationScope{Name: w.serviceName + "/prometheus"},
+				Metrics: convertMetricFamilies(families, w.startTime, time.Now()),
+			}},
+		}},
+	}
+
+	line, err := marshalOTLPJSON(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	return w.file.WriteLine(line)
+}
+
+// Shutdown grava um último snapshot e fecha o arquivo
+func (w *fileMetricsWriter) Shutdown(ctx context.Context) error {
+	w.stopOnce.Do(func() { close(w.stop) })
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	err := w.write()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// convertMetricFamilies converte as famílias do Prometheus para métricas OTLP
+// (counter -> sum monotônica cumulativa, gauge/untyped -> gauge, histogram, summary)
+func convertMetricFamilies(families []*dto.MetricFamily, start, now time.Time) []*metricpb.Metric {
+	startNano := uint64(start.UnixNano())
+	nowNano := uint64(now.UnixNano())
+
+	metrics := make([]*metricpb.Metric, 0, len(families))
+	for _, family := range families {
+		metric := &metricpb.Metric{
+			Name:        family.GetName(),
+			Description: family.GetHelp(),
+		}
+
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			points := make([]*metricpb.NumberDataPoint, 0, len(family.GetMetric()))
+			for _, m := range family.GetMetric() {
+				points = append(points, numberPoint(m, m.GetCounter().GetValue(), startNano, nowNano))
+			}
+			metric.Data = &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+				AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				IsMonotonic:            true,
+				DataPoints:             points,
+			}}
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			points := make([]*metricpb.NumberDataPoint, 0, len(family.GetMetric()))
+			for _, m := range family.GetMetric() {
+				value := m.GetGauge().GetValue()
+				if family.GetType() == dto.MetricType_UNTYPED {
+					value = m.GetUntyped().GetValue()
+				}
+				points = append(points, numberPoint(m, value, 0, nowNano))
+			}
+			metric.Data = &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{DataPoints: points}}
+		case dto.MetricType_HISTOGRAM:
+			points := make([]*metricpb.HistogramDataPoint, 0, len(family.GetMetric()))
+			for _, m := range family.GetMetric() {
+				points = append(points, histogramPoint(m, startNano, nowNano))
+			}
+			metric.Data = &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{
+				AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				DataPoints:             points,
+			}}
+		case dto.MetricType_SUMMARY:
+			points := make([]*metricpb.SummaryDataPoint, 0, len(family.GetMetric()))
+			for _, m := range family.GetMetric() {
+				points = append(points, summaryPoint(m, startNano, nowNano))
+			}
+			metric.Data = &metricpb.Metric_Summary{Summary: &metricpb.Summary{DataPoints: points}}
+		default:
+			continue
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	return metrics
+}
+
+func numberPoint(m *dto.Metric, value float64, startNano, nowNano uint64) *metricpb.NumberDataPoint {
+	return &metricpb.NumberDataPoint{
+		Attributes:        labelsToAttributes(m.GetLabel()),
+		StartTimeUnixNano: startNano,
+		TimeUnixNano:      nowNano,
+		Value:             &metricpb.NumberDataPoint_AsDouble{AsDouble: value},
+	}
+}
+
+// histogramPoint converte os buckets cumulativos do Prometheus em contagens por bucket
+func histogramPoint(m *dto.Metric, startNano, nowNano uint64) *metricpb.HistogramDataPoint {
+	h := m.GetHistogram()
+	sum := h.GetSampleSum()
+
+	point := &metricpb.HistogramDataPoint{
+		Attributes:        labelsToAttributes(m.GetLabel()),
+		StartTimeUnixNano: startNano,
+		TimeUnixNano:      nowNano,
+		Count:             h.GetSampleCount(),
+		Sum:               &sum,
+	}
+
+	var previous uint64
+	for _, bucket := range h.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), +1) {
+			break
+		}
+		point.ExplicitBounds = append(point.ExplicitBounds, bucket.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, bucket.GetCumulativeCount()-previous)
+		previous = bucket.GetCumulativeCount()
+	}
+	// Bucket de overflow (+Inf)
+	point.BucketCounts = append(point.BucketCounts, h.GetSampleCount()-previous)
+
+	return point
+}
+
+func summaryPoint(m *dto.Metric, startNano, nowNano uint64) *metricpb.SummaryDataPoint {
+	s := m.GetSummary()
+
+	point := &metricpb.SummaryDataPoint{
+		Attributes:        labelsToAttributes(m.GetLabel()),
+		StartTimeUnixNano: startNano,
+		TimeUnixNano:      nowNano,
+		Count:             s.GetSampleCount(),
+		Sum:               s.GetSampleSum(),
+	}
+	for _, q := range s.GetQuantile() {
+		point.QuantileValues = append(point.QuantileValues, &metricpb.SummaryDataPoint_ValueAtQuantile{
+			Quantile: q.GetQuantile(),
+			Value:    q.GetValue(),
+		})
+	}
+
+	return point
+}
+
+func labelsToAttributes(labels []*dto.LabelPair) []*commonpb.KeyValue {
+	attrs := make([]*commonpb.KeyValue, 0, len(labels))
+	for _, label := range labels {
+		attrs = append(attrs, stringKV(label.GetName(), label.GetValue()))
+	}
+	return attrs
+}
+
+func stringKV(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
@@ -0,0 +1,123 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// Config configuração do bootstrap de telemetria
+type Config struct {
+	ServiceName string
+	// Exporters lista de exportadores de spans (otlp-grpc, otlp-http, zipkin, stdout, none),
+	// com os nomes em minúsculas
+	Exporters        []string
+	OTLPGRPCEndpoint string
+	OTLPHTTPEndpoint string
+	ZipkinEndpoint   string
+	File             FileConfig
+	Sampler          SamplerConfig
+	Propagators      string
+	BaggageKeys      []string
+	// OTLPTLS segurança da conexão com o collector (gRPC e HTTP)
+	OTLPTLS OTLPTLSConfig
+	// Processors processors adicionais (ex.: SpanStore da página de debug)
+	Processors []sdktrace.SpanProcessor
+}
+
+// OTLPTLSConfig TLS da conexão com o collector
+type OTLPTLSConfig struct {
+	// Insecure usa conexão sem TLS
+	Insecure bool
+	// CAFile CAs aceitas para o collector (vazio usa as CAs do sistema)
+	CAFile string
+	// CertFile e KeyFile certificado de cliente (opcional)
+	CertFile string
+	KeyFile  string
+	// ServerName nome esperado no certificado do collector
+	ServerName string
+	MinVersion uint16
+}
+
+// FileConfig configuração do exportador "file" (modo offline)
+type FileConfig struct {
+	// Dir diretório onde traces.jsonl e metrics.jsonl são gravados
+	Dir string
+	// MaxBytes tamanho máximo de cada arquivo antes da rotação (0 desativa)
+	MaxBytes int64
+	// MaxBackups quantidade de arquivos rotacionados mantidos
+	MaxBackups int
+	// MetricsInterval intervalo entre snapshots de métricas
+	MetricsInterval time.Duration
+}
+
+// InitProvider configura o TracerProvider e o propagador globais.
+// Retorna a função de shutdown que descarrega os spans pendentes.
+func InitProvider(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(cfg.ServiceName),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	propagator, err := NewPropagator(cfg.Propagators)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure propagators: %w", err)
+	}
+
+	exporters, err := newSpanExporters(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	processors := make([]sdktrace.SpanProcessor, 0, len(exporters))
+	for _, exporter := range exporters {
+		processors = append(processors, sdktrace.NewBatchSpanProcessor(exporter))
+	}
+
+	samplingOpts, err := SamplingOptions(cfg.Sampler, processors...)
+	if err != nil {
+		// Encerrar os processors também encerra os exportadores e suas conexões
+		for _, processor := range processors {
+			processor.Shutdown(ctx)
+		}
+		return nil, fmt.Errorf("failed to configure sampler: %w", err)
+	}
+
+	opts := append(samplingOpts,
+		sdktrace.WithResource(res),
+		sdktrace.WithSpanProcessor(NewBaggageSpanProcessor(cfg.BaggageKeys)),
+	)
+	for _, processor := range cfg.Processors {
+		opts = append(opts, sdktrace.WithSpanProcessor(processor))
+	}
+	tracerProvider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tracerProvider)
+
+	otel.SetTextMapPropagator(propagator)
+
+	if !hasExporter(cfg.Exporters, ExporterFile) {
+		return tracerProvider.Shutdown, nil
+	}
+
+	metricsWriter, err := startFileMetrics(cfg.File, cfg.ServiceName, prometheus.DefaultGatherer)
+	if err != nil {
+		tracerProvider.Shutdown(ctx)
+		return nil, fmt.Errorf("failed to create metrics file exporter: %w", err)
+	}
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), metricsWriter.Shutdown(ctx))
+	}, nil
+}
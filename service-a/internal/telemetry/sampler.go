@@ -0,0 +1,229 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SamplingPriorityKey atributo usado pelo collector (tail sampling) para manter o span
+const SamplingPriorityKey = attribute.Key("sampling.priority")
+
+// SamplerConfig configuração de amostragem de traces
+type SamplerConfig struct {
+	// Name segue OTEL_TRACES_SAMPLER (always_on, always_off, traceidratio, parentbased_*)
+	Name string
+	// Arg segue OTEL_TRACES_SAMPLER_ARG (razão entre 0 e 1 para traceidratio)
+	Arg string
+	// KeepErrors força a exportação de spans com status de erro
+	KeepErrors bool
+	// KeepLatency força a exportação de spans mais lentos que o limite (0 desativa)
+	KeepLatency time.Duration
+}
+
+// forcing indica se algum critério de retenção forçada está ativo
+func (c SamplerConfig) forcing() bool {
+	return c.KeepErrors || c.KeepLatency > 0
+}
+
+// NewSampler cria o sampler a partir do nome e argumento no formato das variáveis OTEL
+func NewSampler(name, arg string) (sdktrace.Sampler, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	switch name {
+	case "", "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case "always_on":
+		return sdktrace.AlwaysSample(), nil
+	case "always_off":
+		return sdktrace.NeverSample(), nil
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case "traceidratio":
+		ratio, err := parseRatio(arg)
+		if err != nil {
+			return nil, err
+		}
+		return sdktrace.TraceIDRatioBased(ratio), nil
+	case "parentbased_traceidratio":
+		ratio, err := parseRatio(arg)
+		if err != nil {
+			return nil, err
+		}
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), nil
+	default:
+		return nil, fmt.Errorf("unknown sampler %q", name)
+	}
+}
+
+// rootSampler sampler em uso pelo TracerProvider; pode ser trocado em execução
+var rootSampler = &swappableSampler{}
+
+// swappableSampler delega ao sampler atual, permitindo a troca atômica sem recriar o provider
+type swappableSampler struct {
+	current atomic.Pointer[sdktrace.Sampler]
+}
+
+func (s *swappableSampler) set(sampler sdktrace.Sampler) {
+	s.current.Store(&sampler)
+}
+
+// ShouldSample delega ao sampler atual
+func (s *swappableSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return (*s.current.Load()).ShouldSample(p)
+}
+
+// Description descreve o sampler atual
+func (s *swappableSampler) Description() string {
+	return (*s.current.Load()).Description()
+}
+
+// UpdateSampler troca o sampler (nome e argumento no formato das variáveis OTEL)
+// usado pelo TracerProvider em execução
+func UpdateSampler(name, arg string) error {
+	sampler, err := NewSampler(name, arg)
+	if err != nil {
+		return err
+	}
+	rootSampler.set(sampler)
+	return nil
+}
+
+// parseRatio converte o argumento do sampler em uma razão válida (padrão 1.0)
+func parseRatio(arg string) (float64, error) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return 1.0, nil
+	}
+
+	ratio, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sampler argument %q: %w", arg, err)
+	}
+	if ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("sampler ratio %v out of range [0, 1]", ratio)
+	}
+
+	return ratio, nil
+}
+
+// SamplingOptions monta as opções do TracerProvider para a configuração de amostragem.
+// Os processors informados são envolvidos quando a retenção forçada está ativa.
+func SamplingOptions(cfg SamplerConfig, processors ...sdktrace.SpanProcessor) ([]sdktrace.TracerProviderOption, error) {
+	base, err := NewSampler(cfg.Name, cfg.Arg)
+	if err != nil {
+		return nil, err
+	}
+	rootSampler.set(base)
+	var sampler sdktrace.Sampler = rootSampler
+
+	if !cfg.forcing() {
+		opts := []sdktrace.TracerProviderOption{sdktrace.WithSampler(sampler)}
+		for _, processor := range processors {
+			opts = append(opts, sdktrace.WithSpanProcessor(processor))
+		}
+		return opts, nil
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithSampler(recordingSampler{base: sampler})}
+	for _, processor := range processors {
+		opts = append(opts, sdktrace.WithSpanProcessor(&priorityProcessor{
+			next:        processor,
+			keepErrors:  cfg.KeepErrors,
+			keepLatency: cfg.KeepLatency,
+		}))
+	}
+	return opts, nil
+}
+
+// recordingSampler grava (sem amostrar) os spans descartados pelo sampler base,
+// permitindo que o priorityProcessor decida no fim do span se ele deve ser mantido
+type recordingSampler struct {
+	base sdktrace.Sampler
+}
+
+// ShouldSample delega ao sampler base, trocando Drop por RecordOnly
+func (s recordingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := s.base.ShouldSample(p)
+	if res.Decision == sdktrace.Drop {
+		res.Decision = sdktrace.RecordOnly
+	}
+	return res
+}
+
+// Description descreve o sampler
+func (s recordingSampler) Description() string {
+	return fmt.Sprintf("Recording{%s}", s.base.Description())
+}
+
+// priorityProcessor encaminha spans amostrados e promove spans com erro ou lentos,
+// marcando-os com sampling.priority para que o collector os mantenha
+type priorityProcessor struct {
+	next        sdktrace.SpanProcessor
+	keepErrors  bool
+	keepLatency time.Duration
+}
+
+// OnStart repassa o início do span
+func (p *priorityProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+// OnEnd decide se o span segue para exportação
+func (p *priorityProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if p.keep(s) {
+		p.next.OnEnd(prioritizedSpan{ReadOnlySpan: s})
+		return
+	}
+	if s.SpanContext().IsSampled() {
+		p.next.OnEnd(s)
+	}
+}
+
+// Shutdown encerra o processor encadeado
+func (p *priorityProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+// ForceFlush descarrega o processor encadeado
+func (p *priorityProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// keep verifica se o span atende a algum critério de retenção forçada
+func (p *priorityProcessor) keep(s sdktrace.ReadOnlySpan) bool {
+	if p.keepErrors && s.Status().Code == codes.Error {
+		return true
+	}
+	if p.keepLatency > 0 && s.EndTime().Sub(s.StartTime()) >= p.keepLatency {
+		return true
+	}
+	return false
+}
+
+// prioritizedSpan apresenta o span como amostrado e com sampling.priority=1
+type prioritizedSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+// SpanContext retorna o contexto do span com a flag de amostragem ligada
+func (s prioritizedSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
+
+// Attributes adiciona o atributo de prioridade aos atributos do span
+func (s prioritizedSpan) Attributes() []attribute.KeyValue {
+	attrs := s.ReadOnlySpan.Attributes()
+	out := make([]attribute.KeyValue, 0, len(attrs)+1)
+	out = append(out, attrs...)
+	return append(out, SamplingPriorityKey.Int(1))
+}
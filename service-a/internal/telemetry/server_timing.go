@@ -0,0 +1,118 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServerTimingHeader header HTTP com a decomposição de tempo da requisição
+const ServerTimingHeader = "Server-Timing"
+
+// TimingEntry métrica de tempo no formato do header Server-Timing
+type TimingEntry struct {
+	Name     string
+	Duration time.Duration
+}
+
+// TimingRecorder acumula as métricas de tempo de uma requisição
+type TimingRecorder struct {
+	mu      sync.Mutex
+	entries []TimingEntry
+}
+
+type timingRecorderKey struct{}
+
+// ContextWithTimingRecorder anexa um novo TimingRecorder ao contexto
+func ContextWithTimingRecorder(ctx context.Context) (context.Context, *TimingRecorder) {
+	recorder := &TimingRecorder{}
+	return context.WithValue(ctx, timingRecorderKey{}, recorder), recorder
+}
+
+// TimingFromContext retorna o TimingRecorder do contexto (nil se ausente)
+func TimingFromContext(ctx context.Context) *TimingRecorder {
+	recorder, _ := ctx.Value(timingRecorderKey{}).(*TimingRecorder)
+	return recorder
+}
+
+// TrackTiming inicia a medição de uma etapa; a função retornada registra a duração.
+// Sem TimingRecorder no contexto a medição é ignorada.
+func TrackTiming(ctx context.Context, name string) func() {
+	recorder := TimingFromContext(ctx)
+	if recorder == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		recorder.Add(name, time.Since(start))
+	}
+}
+
+// Add registra a duração de uma etapa
+func (r *TimingRecorder) Add(name string, duration time.Duration) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, TimingEntry{Name: name, Duration: duration})
+}
+
+// Merge registra as métricas recebidas de um serviço downstream com prefixo
+func (r *TimingRecorder) Merge(prefix string, entries []TimingEntry) {
+	for _, entry := range entries {
+		r.Add(prefix+entry.Name, entry.Duration)
+	}
+}
+
+// Entries retorna uma cópia das métricas registradas
+func (r *TimingRecorder) Entries() []TimingEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]TimingEntry(nil), r.entries...)
+}
+
+// FormatServerTiming monta o valor do header Server-Timing (ex.: "validation;dur=0.12, total;dur=35.4")
+func FormatServerTiming(entries []TimingEntry) string {
+	parts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ms := float64(entry.Duration) / float64(time.Millisecond)
+		parts = append(parts, fmt.Sprintf("%s;dur=%.3f", entry.Name, ms))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseServerTiming lê as métricas com duração de um header Server-Timing
+func ParseServerTiming(header string) []TimingEntry {
+	var entries []TimingEntry
+
+	for _, metric := range strings.Split(header, ",") {
+		params := strings.Split(metric, ";")
+		name := strings.TrimSpace(params[0])
+		if name == "" {
+			continue
+		}
+
+		for _, param := range params[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(key, "dur") {
+				continue
+			}
+			ms, err := strconv.ParseFloat(strings.Trim(value, `"`), 64)
+			if err != nil {
+				continue
+			}
+			entries = append(entries, TimingEntry{
+				Name:     name,
+				Duration: time.Duration(ms * float64(time.Millisecond)),
+			})
+		}
+	}
+
+	return entries
+}
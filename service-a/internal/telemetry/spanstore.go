@@ -0,0 +1,265 @@
+package telemetry
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LatencyBounds limites superiores dos buckets de latência da página de debug (estilo zpages)
+var LatencyBounds = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	100 * time.Second,
+}
+
+// SpanSummary contadores agregados por nome de span
+type SpanSummary struct {
+	Name   string
+	Active int
+	Total  int
+	Errors int
+	// Latency contagem por bucket; o último índice conta spans acima do maior limite
+	Latency []int
+}
+
+// SpanStore processor em memória com os spans em andamento e os últimos N finalizados
+type SpanStore struct {
+	mu        sync.Mutex
+	maxSpans  int
+	active    map[trace.SpanID]activeSpan
+	completed []sdktrace.ReadOnlySpan
+	next      int
+	summaries map[string]*SpanSummary
+}
+
+// activeSpan span em andamento com o nome usado no início
+// (o nome pode mudar depois, ex.: rota definida pelo middleware)
+type activeSpan struct {
+	span      sdktrace.ReadOnlySpan
+	startName string
+}
+
+// NewSpanStore cria um SpanStore que mantém até maxSpans spans finalizados
+func NewSpanStore(maxSpans int) *SpanStore {
+	if maxSpans <= 0 {
+		maxSpans = 1000
+	}
+	return &SpanStore{
+		maxSpans:  maxSpans,
+		active:    make(map[trace.SpanID]activeSpan),
+		completed: make([]sdktrace.ReadOnlySpan, 0, maxSpans),
+		summaries: make(map[string]*SpanSummary),
+	}
+}
+
+// OnStart registra o span como em andamento
+func (s *SpanStore) OnStart(_ context.Context, span sdktrace.ReadWriteSpan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.active[span.SpanContext().SpanID()] = activeSpan{span: span, startName: span.Name()}
+	s.summary(span.Name()).Active++
+}
+
+// OnEnd move o span para a lista de finalizados e atualiza os contadores
+func (s *SpanStore) OnEnd(span sdktrace.ReadOnlySpan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if started, ok := s.active[span.SpanContext().SpanID()]; ok {
+		delete(s.active, span.SpanContext().SpanID())
+		s.summary(started.startName).Active--
+	}
+
+	summary := s.summary(span.Name())
+	summary.Total++
+	if span.Status().Code == codes.Error {
+		summary.Errors++
+	}
+	summary.Latency[latencyBucket(span.EndTime().Sub(span.StartTime()))]++
+
+	if len(s.completed) < s.maxSpans {
+		s.completed = append(s.completed, span)
+		return
+	}
+	s.completed[s.next] = span
+	s.next = (s.next + 1) % s.maxSpans
+}
+
+// Shutdown não faz nada
+func (s *SpanStore) Shutdown(context.Context) error { return nil }
+
+// ForceFlush não faz nada
+func (s *SpanStore) ForceFlush(context.Context) error { return nil }
+
+// summary retorna (criando se necessário) o resumo do nome; requer o lock
+func (s *SpanStore) summary(name string) *SpanSummary {
+	summary, ok := s.summaries[name]
+	if !ok {
+		summary = &SpanSummary{Name: name, Latency: make([]int, len(LatencyBounds)+1)}
+		s.summaries[name] = summary
+	}
+	return summary
+}
+
+// Summaries retorna uma cópia dos resumos não vazios ordenada por nome
+func (s *SpanStore) Summaries() []SpanSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summaries := make([]SpanSummary, 0, len(s.summaries))
+	for _, summary := range s.summaries {
+		if summary.Active == 0 && summary.Total == 0 {
+			continue
+		}
+		copied := *summary
+		copied.Latency = append([]int(nil), summary.Latency...)
+		summaries = append(summaries, copied)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+
+	return summaries
+}
+
+// SpansByName retorna os spans em andamento e finalizados com o nome informado,
+// do mais recente para o mais antigo
+func (s *SpanStore) SpansByName(name string) []SpanView {
+	return s.collect(func(span sdktrace.ReadOnlySpan) bool { return span.Name() == name })
+}
+
+// Trace retorna a árvore local de spans do trace (raízes são spans sem pai conhecido)
+func (s *SpanStore) Trace(traceID trace.TraceID) []*SpanView {
+	spans := s.collect(func(span sdktrace.ReadOnlySpan) bool {
+		return span.SpanContext().TraceID() == traceID
+	})
+
+	// Ordenar por início para que os filhos fiquem em ordem cronológica
+	sort.Slice(spans, func(i, j int) bool { return spans[i].StartTime.Before(spans[j].StartTime) })
+
+	nodes := make(map[string]*SpanView, len(spans))
+	for i := range spans {
+		nodes[spans[i].SpanID] = &spans[i]
+	}
+
+	var roots []*SpanView
+	for i := range spans {
+		node := &spans[i]
+		if parent, ok := nodes[node.ParentSpanID]; ok && parent != node {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+
+	return roots
+}
+
+// collect copia os spans que atendem ao filtro (em andamento primeiro, depois finalizados)
+func (s *SpanStore) collect(match func(sdktrace.ReadOnlySpan) bool) []SpanView {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var views []SpanView
+	for _, active := range s.active {
+		if match(active.span) {
+			views = append(views, newSpanView(active.span, true))
+		}
+	}
+
+	for i := len(s.completed) - 1; i >= 0; i-- {
+		// Percorrer o buffer circular do mais recente para o mais antigo
+		span := s.completed[(s.next+i)%len(s.completed)]
+		if match(span) {
+			views = append(views, newSpanView(span, false))
+		}
+	}
+
+	return views
+}
+
+// latencyBucket retorna o índice do bucket da duração
+func latencyBucket(d time.Duration) int {
+	for i, bound := range LatencyBounds {
+		if d < bound {
+			return i
+		}
+	}
+	return len(LatencyBounds)
+}
+
+// SpanView representação serializável de um span para as páginas de debug
+type SpanView struct {
+	TraceID       string         `json:"trace_id"`
+	SpanID        string         `json:"span_id"`
+	ParentSpanID  string         `json:"parent_span_id,omitempty"`
+	Name          string         `json:"name"`
+	Kind          string         `json:"kind"`
+	StartTime     time.Time      `json:"start_time"`
+	EndTime       *time.Time     `json:"end_time,omitempty"`
+	DurationMs    float64        `json:"duration_ms"`
+	InFlight      bool           `json:"in_flight"`
+	Status        string         `json:"status"`
+	StatusMessage string         `json:"status_message,omitempty"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+	Events        []EventView    `json:"events,omitempty"`
+	Children      []*SpanView    `json:"children,omitempty"`
+}
+
+// EventView representação serializável de um evento de span
+type EventView struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// newSpanView copia os dados do span
+func newSpanView(span sdktrace.ReadOnlySpan, inFlight bool) SpanView {
+	view := SpanView{
+		TraceID:       span.SpanContext().TraceID().String(),
+		SpanID:        span.SpanContext().SpanID().String(),
+		Name:          span.Name(),
+		Kind:          span.SpanKind().String(),
+		StartTime:     span.StartTime(),
+		InFlight:      inFlight,
+		Status:        span.Status().Code.String(),
+		StatusMessage: span.Status().Description,
+		Attributes:    make(map[string]any),
+	}
+
+	if span.Parent().IsValid() {
+		view.ParentSpanID = span.Parent().SpanID().String()
+	}
+
+	if inFlight {
+		view.DurationMs = float64(time.Since(span.StartTime())) / float64(time.Millisecond)
+	} else {
+		end := span.EndTime()
+		view.EndTime = &end
+		view.DurationMs = float64(end.Sub(span.StartTime())) / float64(time.Millisecond)
+	}
+
+	for _, attr := range span.Attributes() {
+		view.Attributes[string(attr.Key)] = attr.Value.AsInterface()
+	}
+
+	for _, event := range span.Events() {
+		eventView := EventView{Name: event.Name, Time: event.Time, Attributes: make(map[string]any)}
+		for _, attr := range event.Attributes {
+			eventView.Attributes[string(attr.Key)] = attr.Value.AsInterface()
+		}
+		view.Events = append(view.Events, eventView)
+	}
+
+	return view
+}
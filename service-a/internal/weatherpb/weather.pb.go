@@ -0,0 +1,475 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: weather/v1/weather.proto
+
+// API gRPC do service-b, equivalente ao POST /weather
+
+package weatherpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetWeatherByCEPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cep           string                 `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWeatherByCEPRequest) Reset() {
+	*x = GetWeatherByCEPRequest{}
+	mi := &file_weather_v1_weather_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWeatherByCEPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWeatherByCEPRequest) ProtoMessage() {}
+
+func (x *GetWeatherByCEPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWeatherByCEPRequest.ProtoReflect.Descriptor instead.
+func (*GetWeatherByCEPRequest) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetWeatherByCEPRequest) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+type GetWeatherByCityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	City          string                 `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWeatherByCityRequest) Reset() {
+	*x = GetWeatherByCityRequest{}
+	mi := &file_weather_v1_weather_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWeatherByCityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWeatherByCityRequest) ProtoMessage() {}
+
+func (x *GetWeatherByCityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWeatherByCityRequest.ProtoReflect.Descriptor instead.
+func (*GetWeatherByCityRequest) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetWeatherByCityRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+type Weather struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	City  string                 `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	TempC float64                `protobuf:"fixed64,2,opt,name=temp_c,json=tempC,proto3" json:"temp_c,omitempty"`
+	TempF float64                `protobuf:"fixed64,3,opt,name=temp_f,json=tempF,proto3" json:"temp_f,omitempty"`
+	TempK float64                `protobuf:"fixed64,4,opt,name=temp_k,json=tempK,proto3" json:"temp_k,omitempty"`
+	// source origem dos dados: live, mock ou fixture
+	Source        string `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Weather) Reset() {
+	*x = Weather{}
+	mi := &file_weather_v1_weather_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Weather) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Weather) ProtoMessage() {}
+
+func (x *Weather) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Weather.ProtoReflect.Descriptor instead.
+func (*Weather) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Weather) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Weather) GetTempC() float64 {
+	if x != nil {
+		return x.TempC
+	}
+	return 0
+}
+
+func (x *Weather) GetTempF() float64 {
+	if x != nil {
+		return x.TempF
+	}
+	return 0
+}
+
+func (x *Weather) GetTempK() float64 {
+	if x != nil {
+		return x.TempK
+	}
+	return 0
+}
+
+func (x *Weather) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetWeatherBatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ceps até 100 CEPs por chamada
+	Ceps          []string `protobuf:"bytes,1,rep,name=ceps,proto3" json:"ceps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWeatherBatchRequest) Reset() {
+	*x = GetWeatherBatchRequest{}
+	mi := &file_weather_v1_weather_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWeatherBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWeatherBatchRequest) ProtoMessage() {}
+
+func (x *GetWeatherBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWeatherBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetWeatherBatchRequest) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetWeatherBatchRequest) GetCeps() []string {
+	if x != nil {
+		return x.Ceps
+	}
+	return nil
+}
+
+type WeatherBatchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Cep   string                 `protobuf:"bytes,1,opt,name=cep,proto3" json:"cep,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*WeatherBatchResult_Weather
+	//	*WeatherBatchResult_Error
+	Result        isWeatherBatchResult_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeatherBatchResult) Reset() {
+	*x = WeatherBatchResult{}
+	mi := &file_weather_v1_weather_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeatherBatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherBatchResult) ProtoMessage() {}
+
+func (x *WeatherBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherBatchResult.ProtoReflect.Descriptor instead.
+func (*WeatherBatchResult) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WeatherBatchResult) GetCep() string {
+	if x != nil {
+		return x.Cep
+	}
+	return ""
+}
+
+func (x *WeatherBatchResult) GetResult() isWeatherBatchResult_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *WeatherBatchResult) GetWeather() *Weather {
+	if x != nil {
+		if x, ok := x.Result.(*WeatherBatchResult_Weather); ok {
+			return x.Weather
+		}
+	}
+	return nil
+}
+
+func (x *WeatherBatchResult) GetError() *BatchError {
+	if x != nil {
+		if x, ok := x.Result.(*WeatherBatchResult_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isWeatherBatchResult_Result interface {
+	isWeatherBatchResult_Result()
+}
+
+type WeatherBatchResult_Weather struct {
+	Weather *Weather `protobuf:"bytes,2,opt,name=weather,proto3,oneof"`
+}
+
+type WeatherBatchResult_Error struct {
+	Error *BatchError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*WeatherBatchResult_Weather) isWeatherBatchResult_Result() {}
+
+func (*WeatherBatchResult_Error) isWeatherBatchResult_Result() {}
+
+// BatchError falha da consulta de um CEP do lote
+type BatchError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// code código gRPC (google.rpc.Code) que a chamada unária retornaria
+	Code          int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchError) Reset() {
+	*x = BatchError{}
+	mi := &file_weather_v1_weather_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchError) ProtoMessage() {}
+
+func (x *BatchError) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_v1_weather_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchError.ProtoReflect.Descriptor instead.
+func (*BatchError) Descriptor() ([]byte, []int) {
+	return file_weather_v1_weather_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_weather_v1_weather_proto protoreflect.FileDescriptor
+
+const file_weather_v1_weather_proto_rawDesc = "" +
+	"\n" +
+	"\x18weather/v1/weather.proto\x12\n" +
+	"weather.v1\"*\n" +
+	"\x16GetWeatherByCEPRequest\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\"-\n" +
+	"\x17GetWeatherByCityRequest\x12\x12\n" +
+	"\x04city\x18\x01 \x01(\tR\x04city\"z\n" +
+	"\aWeather\x12\x12\n" +
+	"\x04city\x18\x01 \x01(\tR\x04city\x12\x15\n" +
+	"\x06temp_c\x18\x02 \x01(\x01R\x05tempC\x12\x15\n" +
+	"\x06temp_f\x18\x03 \x01(\x01R\x05tempF\x12\x15\n" +
+	"\x06temp_k\x18\x04 \x01(\x01R\x05tempK\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\",\n" +
+	"\x16GetWeatherBatchRequest\x12\x12\n" +
+	"\x04ceps\x18\x01 \x03(\tR\x04ceps\"\x91\x01\n" +
+	"\x12WeatherBatchResult\x12\x10\n" +
+	"\x03cep\x18\x01 \x01(\tR\x03cep\x12/\n" +
+	"\aweather\x18\x02 \x01(\v2\x13.weather.v1.WeatherH\x00R\aweather\x12.\n" +
+	"\x05error\x18\x03 \x01(\v2\x16.weather.v1.BatchErrorH\x00R\x05errorB\b\n" +
+	"\x06result\":\n" +
+	"\n" +
+	"BatchError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\x83\x02\n" +
+	"\x0eWeatherService\x12J\n" +
+	"\x0fGetWeatherByCEP\x12\".weather.v1.GetWeatherByCEPRequest\x1a\x13.weather.v1.Weather\x12L\n" +
+	"\x10GetWeatherByCity\x12#.weather.v1.GetWeatherByCityRequest\x1a\x13.weather.v1.Weather\x12W\n" +
+	"\x0fGetWeatherBatch\x12\".weather.v1.GetWeatherBatchRequest\x1a\x1e.weather.v1.WeatherBatchResult0\x01b\x06proto3"
+
+var (
+	file_weather_v1_weather_proto_rawDescOnce sync.Once
+	file_weather_v1_weather_proto_rawDescData []byte
+)
+
+func file_weather_v1_weather_proto_rawDescGZIP() []byte {
+	file_weather_v1_weather_proto_rawDescOnce.Do(func() {
+		file_weather_v1_weather_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_weather_v1_weather_proto_rawDesc), len(file_weather_v1_weather_proto_rawDesc)))
+	})
+	return file_weather_v1_weather_proto_rawDescData
+}
+
+var file_weather_v1_weather_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_weather_v1_weather_proto_goTypes = []any{
+	(*GetWeatherByCEPRequest)(nil),  // 0: weather.v1.GetWeatherByCEPRequest
+	(*GetWeatherByCityRequest)(nil), // 1: weather.v1.GetWeatherByCityRequest
+	(*Weather)(nil),                 // 2: weather.v1.Weather
+	(*GetWeatherBatchRequest)(nil),  // 3: weather.v1.GetWeatherBatchRequest
+	(*WeatherBatchResult)(nil),      // 4: weather.v1.WeatherBatchResult
+	(*BatchError)(nil),              // 5: weather.v1.BatchError
+}
+var file_weather_v1_weather_proto_depIdxs = []int32{
+	2, // 0: weather.v1.WeatherBatchResult.weather:type_name -> weather.v1.Weather
+	5, // 1: weather.v1.WeatherBatchResult.error:type_name -> weather.v1.BatchError
+	0, // 2: weather.v1.WeatherService.GetWeatherByCEP:input_type -> weather.v1.GetWeatherByCEPRequest
+	1, // 3: weather.v1.WeatherService.GetWeatherByCity:input_type -> weather.v1.GetWeatherByCityRequest
+	3, // 4: weather.v1.WeatherService.GetWeatherBatch:input_type -> weather.v1.GetWeatherBatchRequest
+	2, // 5: weather.v1.WeatherService.GetWeatherByCEP:output_type -> weather.v1.Weather
+	2, // 6: weather.v1.WeatherService.GetWeatherByCity:output_type -> weather.v1.Weather
+	4, // 7: weather.v1.WeatherService.GetWeatherBatch:output_type -> weather.v1.WeatherBatchResult
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_weather_v1_weather_proto_init() }
+func file_weather_v1_weather_proto_init() {
+	if File_weather_v1_weather_proto != nil {
+		return
+	}
+	file_weather_v1_weather_proto_msgTypes[4].OneofWrappers = []any{
+		(*WeatherBatchResult_Weather)(nil),
+		(*WeatherBatchResult_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_weather_v1_weather_proto_rawDesc), len(file_weather_v1_weather_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_weather_v1_weather_proto_goTypes,
+		DependencyIndexes: file_weather_v1_weather_proto_depIdxs,
+		MessageInfos:      file_weather_v1_weather_proto_msgTypes,
+	}.Build()
+	File_weather_v1_weather_proto = out.File
+	file_weather_v1_weather_proto_goTypes = nil
+	file_weather_v1_weather_proto_depIdxs = nil
+}
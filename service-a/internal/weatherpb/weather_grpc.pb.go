@@ -0,0 +1,219 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: weather/v1/weather.proto
+
+// API gRPC do service-b, equivalente ao POST /weather
+
+package weatherpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WeatherService_GetWeatherByCEP_FullMethodName  = "/weather.v1.WeatherService/GetWeatherByCEP"
+	WeatherService_GetWeatherByCity_FullMethodName = "/weather.v1.WeatherService/GetWeatherByCity"
+	WeatherService_GetWeatherBatch_FullMethodName  = "/weather.v1.WeatherService/GetWeatherBatch"
+)
+
+// WeatherServiceClient is the client API for WeatherService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WeatherService consulta o clima atual por CEP ou cidade
+type WeatherServiceClient interface {
+	// GetWeatherByCEP resolve a cidade do CEP no ViaCEP e retorna o clima atual.
+	// Erros: INVALID_ARGUMENT (CEP inválido), NOT_FOUND (CEP ou cidade não
+	// encontrados), RESOURCE_EXHAUSTED (cota do provedor esgotada).
+	GetWeatherByCEP(ctx context.Context, in *GetWeatherByCEPRequest, opts ...grpc.CallOption) (*Weather, error)
+	// GetWeatherByCity retorna o clima atual da cidade
+	GetWeatherByCity(ctx context.Context, in *GetWeatherByCityRequest, opts ...grpc.CallOption) (*Weather, error)
+	// GetWeatherBatch consulta vários CEPs e envia um resultado por CEP, na ordem
+	// do pedido, assim que cada consulta termina
+	GetWeatherBatch(ctx context.Context, in *GetWeatherBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WeatherBatchResult], error)
+}
+
+type weatherServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWeatherServiceClient(cc grpc.ClientConnInterface) WeatherServiceClient {
+	return &weatherServiceClient{cc}
+}
+
+func (c *weatherServiceClient) GetWeatherByCEP(ctx context.Context, in *GetWeatherByCEPRequest, opts ...grpc.CallOption) (*Weather, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Weather)
+	err := c.cc.Invoke(ctx, WeatherService_GetWeatherByCEP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) GetWeatherByCity(ctx context.Context, in *GetWeatherByCityRequest, opts ...grpc.CallOption) (*Weather, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Weather)
+	err := c.cc.Invoke(ctx, WeatherService_GetWeatherByCity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) GetWeatherBatch(ctx context.Context, in *GetWeatherBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WeatherBatchResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WeatherService_ServiceDesc.Streams[0], WeatherService_GetWeatherBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetWeatherBatchRequest, WeatherBatchResult]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherService_GetWeatherBatchClient = grpc.ServerStreamingClient[WeatherBatchResult]
+
+// WeatherServiceServer is the server API for WeatherService service.
+// All implementations must embed UnimplementedWeatherServiceServer
+// for forward compatibility.
+//
+// WeatherService consulta o clima atual por CEP ou cidade
+type WeatherServiceServer interface {
+	// GetWeatherByCEP resolve a cidade do CEP no ViaCEP e retorna o clima atual.
+	// Erros: INVALID_ARGUMENT (CEP inválido), NOT_FOUND (CEP ou cidade não
+	// encontrados), RESOURCE_EXHAUSTED (cota do provedor esgotada).
+	GetWeatherByCEP(context.Context, *GetWeatherByCEPRequest) (*Weather, error)
+	// GetWeatherByCity retorna o clima atual da cidade
+	GetWeatherByCity(context.Context, *GetWeatherByCityRequest) (*Weather, error)
+	// GetWeatherBatch consulta vários CEPs e envia um resultado por CEP, na ordem
+	// do pedido, assim que cada consulta termina
+	GetWeatherBatch(*GetWeatherBatchRequest, grpc.ServerStreamingServer[WeatherBatchResult]) error
+	mustEmbedUnimplementedWeatherServiceServer()
+}
+
+// UnimplementedWeatherServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWeatherServiceServer struct{}
+
+func (UnimplementedWeatherServiceServer) GetWeatherByCEP(context.Context, *GetWeatherByCEPRequest) (*Weather, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWeatherByCEP not implemented")
+}
+func (UnimplementedWeatherServiceServer) GetWeatherByCity(context.Context, *GetWeatherByCityRequest) (*Weather, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWeatherByCity not implemented")
+}
+func (UnimplementedWeatherServiceServer) GetWeatherBatch(*GetWeatherBatchRequest, grpc.ServerStreamingServer[WeatherBatchResult]) error {
+	return status.Errorf(codes.Unimplemented, "method GetWeatherBatch not implemented")
+}
+func (UnimplementedWeatherServiceServer) mustEmbedUnimplementedWeatherServiceServer() {}
+func (UnimplementedWeatherServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeWeatherServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WeatherServiceServer will
+// result in compilation errors.
+type UnsafeWeatherServiceServer interface {
+	mustEmbedUnimplementedWeatherServiceServer()
+}
+
+func RegisterWeatherServiceServer(s grpc.ServiceRegistrar, srv WeatherServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWeatherServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WeatherService_ServiceDesc, srv)
+}
+
+func _WeatherService_GetWeatherByCEP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWeatherByCEPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetWeatherByCEP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetWeatherByCEP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetWeatherByCEP(ctx, req.(*GetWeatherByCEPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_GetWeatherByCity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWeatherByCityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetWeatherByCity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetWeatherByCity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetWeatherByCity(ctx, req.(*GetWeatherByCityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_GetWeatherBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetWeatherBatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WeatherServiceServer).GetWeatherBatch(m, &grpc.GenericServerStream[GetWeatherBatchRequest, WeatherBatchResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherService_GetWeatherBatchServer = grpc.ServerStreamingServer[WeatherBatchResult]
+
+// WeatherService_ServiceDesc is the grpc.ServiceDesc for WeatherService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WeatherService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "weather.v1.WeatherService",
+	HandlerType: (*WeatherServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetWeatherByCEP",
+			Handler:    _WeatherService_GetWeatherByCEP_Handler,
+		},
+		{
+			MethodName: "GetWeatherByCity",
+			Handler:    _WeatherService_GetWeatherByCity_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetWeatherBatch",
+			Handler:       _WeatherService_GetWeatherBatch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "weather/v1/weather.proto",
+}
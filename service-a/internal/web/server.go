@@ -1,13 +1,25 @@
 package web
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
+	"github.com/marfebr/otel-lab/service-a/internal/auth"
+	"github.com/marfebr/otel-lab/service-a/internal/config"
+	"github.com/marfebr/otel-lab/service-a/internal/deadline"
+	"github.com/marfebr/otel-lab/service-a/internal/fault"
 	"github.com/marfebr/otel-lab/service-a/internal/handler"
+	"github.com/marfebr/otel-lab/service-a/internal/health"
+	"github.com/marfebr/otel-lab/service-a/internal/httpclient"
+	"github.com/marfebr/otel-lab/service-a/internal/idempotency"
+	"github.com/marfebr/otel-lab/service-a/internal/peerauth"
+	"github.com/marfebr/otel-lab/service-a/internal/ratelimit"
 	"github.com/marfebr/otel-lab/service-a/internal/service"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/marfebr/otel-lab/service-a/internal/shed"
+	"github.com/marfebr/otel-lab/service-a/internal/telemetry"
+	"github.com/marfebr/otel-lab/service-a/internal/tlsconfig"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -15,42 +27,164 @@ import (
 type Server struct {
 	router     *chi.Mux
 	cepHandler *handler.CEPHandler
+	checker    *health.Checker
+	limiter    *ratelimit.Limiter
+	faults     *fault.Injector
 	tracer     trace.Tracer
 }
 
 // NewServer cria uma nova instância do servidor
-func NewServer(tracer trace.Tracer, serviceBURL string) *Server {
+func NewServer(tracer trace.Tracer, cfg *config.Config, spanStore *telemetry.SpanStore) (*Server, error) {
+	// Criar injeção de falhas (apenas para testes)
+	var faults *fault.Injector
+	if cfg.Fault.Enabled {
+		faults = fault.NewInjector(cfg.Fault.Rules)
+	}
+
 	// Criar validador de CEP
 	cepValidator := service.NewCEPValidator()
 
 	// Criar cliente do Serviço B
-	serviceBClient := service.NewServiceBClient(serviceBURL, tracer)
+	serviceBClient, serviceBTarget, err := newServiceBClient(cfg, tracer, faults)
+	if err != nil {
+		return nil, err
+	}
 
-	// Criar serviço de clima
 	weatherService := service.NewWeatherService(serviceBClient, tracer)
 
 	// Criar handler de CEP
 	cepHandler := handler.NewCEPHandler(cepValidator, weatherService, tracer)
 
+	// Criar verificação de prontidão (depende do Serviço B)
+	checker := health.NewChecker(cfg.HealthCacheTTL, 3*time.Second, health.Check{
+		Name: "service-b",
+		Run: func(ctx context.Context) (string, error) {
+			return serviceBTarget, serviceBClient.Ping(ctx)
+		},
+	})
+
+	// Criar autenticação por chave de API do POST /cep
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		keys, err := auth.LoadKeys(cfg.Auth.KeysFile, cfg.Auth.Keys)
+		if err != nil {
+			return nil, err
+		}
+		authenticator = auth.NewAuthenticator(keys, auth.NewUsage())
+	}
+
+	// Criar rate limiter por cliente do POST /cep
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		store, err := newRateLimitStore(cfg.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+		limiter = ratelimit.NewLimiter(store, cfg.RateLimit.Limits)
+	}
+
+	// Criar deduplicação do POST /cep por Idempotency-Key
+	var idempotencyCache *idempotency.Cache
+	if cfg.Idempotency.Enabled {
+		idempotencyCache = idempotency.NewCache(cfg.Idempotency.TTL, cfg.Idempotency.MaxKeys)
+	}
+
 	// Criar router
 	router := chi.NewRouter()
 
 	// Configurar middleware
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
+	router.Use(auth.StripBaggage)
+	router.Use(tracingMiddleware("service-a"))
+	router.Use(metricsMiddleware)
+	router.Use(serverTimingMiddleware)
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Logger)
-	router.Use(middleware.Timeout(60 * time.Second))
+	router.Use(shed.NewLimiter(cfg.Shed).Middleware(priority))
+	router.Use(deadline.Middleware(cfg.RequestTimeout))
+	router.Use(faults.Middleware)
 
 	// Configurar endpoints
-	router.Handle("/metrics", promhttp.Handler())
-	router.Post("/cep", cepHandler.HandleCEPValidation)
+	router.Handle("/metrics", metricsHandler())
+	router.Get("/healthz", checker.HandleLiveness)
+	router.Get("/readyz", checker.HandleReadiness)
+	if spanStore != nil {
+		debug := &debugHandler{service: "service-a", store: spanStore}
+		router.Get("/debug/traces", debug.handleTraces)
+		router.Get("/debug/traces/{traceID}", debug.handleTrace)
+	}
+	router.Group(func(r chi.Router) {
+		if authenticator != nil {
+			r.Use(authenticator.Middleware)
+		}
+		if limiter != nil {
+			r.Use(limiter.Middleware)
+		}
+		if idempotencyCache != nil {
+			r.Use(idempotencyCache.Middleware)
+		}
+		r.Post("/cep", cepHandler.HandleCEPValidation)
+	})
+	if authenticator != nil {
+		router.With(authenticator.Middleware, authenticator.RequireAdmin).Get("/admin/usage", authenticator.HandleUsage)
+	}
 
 	return &Server{
 		router:     router,
 		cepHandler: cepHandler,
+		checker:    checker,
+		limiter:    limiter,
+		faults:     faults,
 		tracer:     tracer,
+	}, nil
+}
+
+// newRateLimitStore cria o store de buckets configurado
+func newRateLimitStore(cfg config.RateLimitConfig) (ratelimit.Store, error) {
+	if cfg.Store == "redis" {
+		return ratelimit.NewRedisStore(cfg.RedisURL, "ratelimit:service-a:")
+	}
+	return ratelimit.NewMemoryStore(), nil
+}
+
+// newServiceBClient cria o cliente do Serviço B no transporte configurado (HTTP ou
+// gRPC), com TLS, autenticação entre serviços e as falhas injetadas no upstream
+// service-b; retorna também o endereço usado
+func newServiceBClient(cfg *config.Config, tracer trace.Tracer, faults *fault.Injector) (*service.ServiceBClient, string, error) {
+	// No modo mtls a CA e o certificado de cliente vêm de SERVICE_AUTH_*
+	tlsConfig, err := tlsconfig.ClientConfig(tlsconfig.ClientOptions{
+		CAFile:     cfg.ServiceBCAFile,
+		MinVersion: cfg.TLS.MinVersion,
+	})
+	if cfg.ServiceAuth.Mode == peerauth.ModeMTLS {
+		tlsConfig, err = peerauth.ClientTLSConfig(cfg.ServiceAuth)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if cfg.ServiceBTransport == "grpc" {
+		if !cfg.ServiceBGRPCTLS {
+			tlsConfig = nil
+		}
+		client, err := service.NewServiceBGRPCClient(cfg.ServiceBGRPCAddr, cfg.ServiceBTimeout, tracer, tlsConfig, cfg.ServiceAuth, faults)
+		return client, "grpc://" + cfg.ServiceBGRPCAddr, err
+	}
+
+	// Transporte único do Serviço B, compartilhado pelas chamadas e pelo /readyz
+	base := httpclient.NewTransport("service-b", cfg.HTTPClient, tlsConfig)
+	transport := peerauth.NewTransport(cfg.ServiceAuth, base)
+	return service.NewServiceBClient(cfg.ServiceBURL, cfg.ServiceBTimeout, tracer, transport, faults), cfg.ServiceBURL, nil
+}
+
+// ApplyConfig aplica as configurações recarregadas em execução
+func (s *Server) ApplyConfig(cfg *config.Config) {
+	s.checker.SetTTL(cfg.HealthCacheTTL)
+	if s.limiter != nil {
+		s.limiter.SetLimits(cfg.RateLimit.Limits)
 	}
+	s.faults.SetRules(cfg.Fault.Rules)
 }
 
 // GetRouter retorna o router configurado
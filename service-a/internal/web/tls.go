@@ -0,0 +1,17 @@
+package web
+
+import (
+	"crypto/tls"
+
+	"github.com/marfebr/otel-lab/service-a/internal/config"
+	"github.com/marfebr/otel-lab/service-a/internal/tlsconfig"
+)
+
+// ListenerTLSConfig configuração TLS do listener HTTP; nil quando nenhum certificado
+// foi configurado (HTTP puro)
+func ListenerTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	if cfg.TLS.CertFile == "" {
+		return nil, nil
+	}
+	return tlsconfig.ServerConfig(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.MinVersion)
+}
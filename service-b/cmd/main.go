@@ -2,119 +2,169 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"flag"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"time"
 
+	"github.com/marfebr/otel-lab/service-b/internal/config"
+	"github.com/marfebr/otel-lab/service-b/internal/logging"
+	"github.com/marfebr/otel-lab/service-b/internal/peerauth"
+	"github.com/marfebr/otel-lab/service-b/internal/rpc"
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
 	"github.com/marfebr/otel-lab/service-b/internal/web"
-	"github.com/spf13/viper"
-
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"google.golang.org/grpc"
 )
 
-func initProvider(serviceName, collectorURL string) (func(context.Context) error, error) {
-	ctx := context.Background()
-
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(serviceName),
-		),
-	)
+// replay reenvia ao collector os arquivos gravados pelo exportador "file"
+// Uso: <binário> replay [-endpoint host:porta] arquivo.jsonl...
+func replay(args []string) {
+	cfg, err := config.Load(nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create resource: %w", err)
+		log.Fatalf("invalid configuration:\n%v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, time.Second)
-	defer cancel()
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	endpoint := flags.String("endpoint", cfg.Telemetry.OTLPGRPCEndpoint, "OTLP gRPC endpoint of the collector")
+	timeout := flags.Duration("timeout", time.Minute, "timeout for the whole replay")
+	flags.Parse(args)
 
-	conn, err := grpc.NewClient(collectorURL,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create gRPC connection to collector: %w", err)
+	if flags.NArg() == 0 {
+		log.Fatal("usage: replay [-endpoint host:port] [-timeout 1m] file.jsonl...")
 	}
 
-	traceExporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	stats, err := telemetry.Replay(ctx, *endpoint, cfg.Telemetry.OTLPTLS, flags.Args())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+		log.Fatal(err)
 	}
-
-	bsp := sdktrace.NewBatchSpanProcessor(traceExporter)
-	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-		sdktrace.WithResource(res),
-		sdktrace.WithSpanProcessor(bsp),
-	)
-	otel.SetTracerProvider(tracerProvider)
-
-	otel.SetTextMapPropagator(propagation.TraceContext{})
-
-	return tracerProvider.Shutdown, nil
-}
-
-// load env vars cfg
-func init() {
-	viper.AutomaticEnv()
-	// Configurar valores padrão
-	viper.SetDefault("HTTP_PORT", ":8181")
-	viper.SetDefault("OTEL_SERVICE_NAME", "service-b")
-	viper.SetDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "otel-collector:4317")
-	viper.SetDefault("VIACEP_BASE_URL", "https://viacep.com.br/ws")
-	viper.SetDefault("OPENWEATHER_BASE_URL", "https://api.openweathermap.org/data/2.5")
-	viper.SetDefault("OPENWEATHER_API_KEY", "")
+	log.Printf("Replay to %s finished: %d trace batches, %d metric batches, %d lines skipped",
+		*endpoint, stats.TraceBatches, stats.MetricBatches, stats.Skipped)
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		replay(os.Args[2:])
+		return
+	}
+
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, config.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+	if err := logging.Setup(cfg.LogLevel); err != nil {
+		log.Fatal(err)
+	}
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	shutdown, err := initProvider(viper.GetString("OTEL_SERVICE_NAME"), viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"))
+	// Página /debug/traces alimentada por spans em memória (desligada por padrão)
+	var spanStore *telemetry.SpanStore
+	if cfg.DebugTraces.Enabled {
+		spanStore = telemetry.NewSpanStore(cfg.DebugTraces.MaxSpans)
+		cfg.Telemetry.Processors = append(cfg.Telemetry.Processors, spanStore)
+	}
+
+	shutdown, err := telemetry.InitProvider(ctx, cfg.Telemetry)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer func() {
-		if err := shutdown(ctx); err != nil {
-			log.Fatal("failed to shutdown TracerProvider: %w", err)
+		// Falhas ao descarregar spans (ex.: collector ausente) não devem derrubar o processo
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer flushCancel()
+		if err := shutdown(flushCtx); err != nil {
+			log.Printf("failed to shutdown TracerProvider: %v", err)
 		}
 	}()
 
 	tracer := otel.Tracer("service-b-tracer")
 
 	// Criar servidor web
-	server := web.NewServer(tracer)
+	server, err := web.NewServer(tracer, cfg, spanStore)
+	if err != nil {
+		log.Fatal(err)
+	}
 	router := server.GetRouter()
 
+	// Recarregar as configurações seguras ao alterar o arquivo ou receber SIGHUP
+	watcher := config.NewWatcher(cfg, os.Args[1:])
+	watcher.OnReload(func(cfg *config.Config) {
+		if err := logging.SetLevel(cfg.LogLevel); err != nil {
+			log.Printf("failed to apply log level: %v", err)
+		}
+		if err := telemetry.UpdateSampler(cfg.Telemetry.Sampler.Name, cfg.Telemetry.Sampler.Arg); err != nil {
+			log.Printf("failed to apply sampler: %v", err)
+		}
+	})
+	watcher.OnReload(server.ApplyConfig)
+	if err := watcher.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+
 	// Configurar servidor HTTP
 	httpServer := &http.Server{
-		Addr:    viper.GetString("HTTP_PORT"),
+		Addr:    cfg.HTTPPort,
 		Handler: router,
 	}
+	// HTTPS quando há certificado configurado (recarregado ao mudar os arquivos)
+	httpServer.TLSConfig, err = web.ListenerTLSConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	log.Printf("Service B initialized with OTEL tracing")
-	log.Printf("ViaCEP URL: %s", viper.GetString("VIACEP_BASE_URL"))
-	log.Printf("OpenWeather URL: %s", viper.GetString("OPENWEATHER_BASE_URL"))
+	log.Printf("ViaCEP URL: %s", cfg.ViaCEPBaseURL)
+	log.Printf("Weather provider mode: %s", cfg.Weather.Mode)
+	log.Printf("Service auth mode: %s", cfg.ServiceAuth.Mode)
+	if cfg.Weather.Mode != "live" {
+		log.Printf("Weather data is NOT real: responses carry X-Weather-Source: %s", cfg.Weather.Mode)
+	}
 
 	go func() {
-		log.Println("Starting Service B on port", viper.GetString("HTTP_PORT"))
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Println("Starting Service B on port", cfg.HTTPPort)
+		var err error
+		if httpServer.TLSConfig != nil {
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server:", err)
 		}
 	}()
 
+	// Servidor gRPC do WeatherService, com o mesmo TLS e a mesma autenticação do HTTP
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != "" {
+		listener, err := net.Listen("tcp", cfg.GRPCPort)
+		if err != nil {
+			log.Fatal("Failed to listen for gRPC:", err)
+		}
+		grpcServer = rpc.NewServer(rpc.NewWeatherServer(server.Orchestrator()), server.Ready, peerauth.NewVerifier(cfg.ServiceAuth), server.Admission(), server.Faults(), httpServer.TLSConfig, cfg.RequestTimeout)
+		go func() {
+			log.Println("Starting Service B gRPC on port", cfg.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal("Failed to start gRPC server:", err)
+			}
+		}()
+	}
+
 	select {
 	case <-sigCh:
 		log.Println("Shutting down gracefully, CTRL+C pressed...")
@@ -129,4 +179,16 @@ func main() {
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Server shutdown error: %v", err)
 	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}
 }
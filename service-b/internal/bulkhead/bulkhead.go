@@ -0,0 +1,161 @@
+// Package bulkhead limita as chamadas simultâneas a cada dependência externa, para
+// que um provedor lento não prenda todas as goroutines e conexões do serviço
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-b/internal/semaphore"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Motivos de rejeição
+const (
+	ReasonQueueFull    = "queue_full"
+	ReasonQueueTimeout = "queue_timeout"
+)
+
+// ErrRejected erro retornado quando não há vaga para a chamada
+var ErrRejected = errors.New("upstream concurrency limit reached")
+
+// RejectedError detalha a rejeição; errors.Is(err, ErrRejected) é verdadeiro
+type RejectedError struct {
+	Dependency string
+	Reason     string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("upstream concurrency limit reached: %s (%s)", e.Dependency, e.Reason)
+}
+
+// Is permite comparar com ErrRejected
+func (e *RejectedError) Is(target error) bool {
+	return target == ErrRejected
+}
+
+// Limits limites de uma dependência. No modo adaptativo o limite começa em
+// MaxConcurrent e varia entre MinConcurrent e MaxConcurrent (AIMD): cresce uma
+// vaga a cada limite de chamadas abaixo de LatencyTarget e cai 10% a cada chamada
+// mais lenta ou que estourou o prazo.
+type Limits struct {
+	MaxConcurrent int
+	// MaxQueue chamadas que podem esperar por vaga (0 rejeita assim que o limite é atingido)
+	MaxQueue int
+	// QueueTimeout espera máxima na fila
+	QueueTimeout time.Duration
+
+	Adaptive      bool
+	MinConcurrent int
+	LatencyTarget time.Duration
+}
+
+// backoff fator de redução do limite adaptativo
+const backoff = 0.9
+
+var (
+	inFlightGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "bulkhead_in_flight",
+		Help: "Calls holding a concurrency slot by dependency.",
+	}, []string{"dependency"})
+
+	queuedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "bulkhead_queued",
+		Help: "Calls waiting for a concurrency slot by dependency.",
+	}, []string{"dependency"})
+
+	limitGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "bulkhead_limit",
+		Help: "Current concurrency limit by dependency (changes over time in adaptive mode).",
+	}, []string{"dependency"})
+
+	rejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bulkhead_rejections_total",
+		Help: "Calls rejected for lack of a concurrency slot by dependency and reason (queue_full, queue_timeout).",
+	}, []string{"dependency", "reason"})
+)
+
+// Bulkhead semáforo de uma dependência com fila de espera limitada e em ordem de chegada
+type Bulkhead struct {
+	dependency string
+	limits     Limits
+	sem        *semaphore.Semaphore
+
+	mu    sync.Mutex
+	limit float64
+}
+
+// New cria o Bulkhead da dependência; MaxConcurrent zero desativa o limite (retorna nil)
+func New(dependency string, limits Limits) *Bulkhead {
+	if limits.MaxConcurrent <= 0 {
+		return nil
+	}
+	b := &Bulkhead{
+		dependency: dependency,
+		limits:     limits,
+		limit:      float64(limits.MaxConcurrent),
+		sem: semaphore.New(limits.MaxConcurrent, limits.MaxQueue, func(inFlight, queued int) {
+			inFlightGauge.WithLabelValues(dependency).Set(float64(inFlight))
+			queuedGauge.WithLabelValues(dependency).Set(float64(queued))
+		}),
+	}
+	limitGauge.WithLabelValues(dependency).Set(b.limit)
+	return b
+}
+
+// Acquire reserva uma vaga, esperando na fila até QueueTimeout se o limite foi
+// atingido. A função retornada libera a vaga e deve ser chamada com o erro da
+// chamada, usado pelo modo adaptativo. Um Bulkhead nil não limita.
+func (b *Bulkhead) Acquire(ctx context.Context) (func(error), error) {
+	if b == nil {
+		return func(error) {}, nil
+	}
+
+	_, err := b.sem.Acquire(ctx, b.limits.QueueTimeout)
+	switch {
+	case errors.Is(err, semaphore.ErrQueueFull):
+		return nil, b.reject(ReasonQueueFull)
+	case errors.Is(err, semaphore.ErrQueueTimeout):
+		return nil, b.reject(ReasonQueueTimeout)
+	case err != nil:
+		return nil, err
+	}
+	return b.releaser(time.Now()), nil
+}
+
+// releaser libera a vaga uma única vez, ajustando antes o limite adaptativo
+func (b *Bulkhead) releaser(start time.Time) func(error) {
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			if b.limits.Adaptive {
+				b.adapt(time.Since(start), err)
+			}
+			b.sem.Release()
+		})
+	}
+}
+
+// adapt aumenta o limite aditivamente nas chamadas rápidas e o reduz
+// multiplicativamente nas lentas ou que estouraram o prazo
+func (b *Bulkhead) adapt(latency time.Duration, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if latency > b.limits.LatencyTarget || errors.Is(err, context.DeadlineExceeded) {
+		b.limit = max(b.limit*backoff, float64(b.limits.MinConcurrent))
+	} else if err == nil {
+		b.limit = min(b.limit+1/b.limit, float64(b.limits.MaxConcurrent))
+	}
+	limitGauge.WithLabelValues(b.dependency).Set(b.limit)
+	b.sem.SetLimit(int(b.limit))
+}
+
+func (b *Bulkhead) reject(reason string) error {
+	rejections.WithLabelValues(b.dependency, reason).Inc()
+	return &RejectedError{Dependency: b.dependency, Reason: reason}
+}
@@ -0,0 +1,44 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcquireRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		reason string
+	}{
+		{
+			name:   "queue full",
+			limits: Limits{MaxConcurrent: 1, QueueTimeout: time.Minute},
+			reason: ReasonQueueFull,
+		},
+		{
+			name:   "queue timeout",
+			limits: Limits{MaxConcurrent: 1, MaxQueue: 1, QueueTimeout: 10 * time.Millisecond},
+			reason: ReasonQueueTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New("test", tt.limits)
+			release, err := b.Acquire(context.Background())
+			if err != nil {
+				t.Fatalf("Acquire() error = %v", err)
+			}
+			defer release(nil)
+
+			_, err = b.Acquire(context.Background())
+			var rejected *RejectedError
+			if !errors.As(err, &rejected) || rejected.Reason != tt.reason || !errors.Is(err, ErrRejected) {
+				t.Fatalf("Acquire() error = %v, want RejectedError with reason %s", err, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,694 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-b/internal/bulkhead"
+	"github.com/marfebr/otel-lab/service-b/internal/fault"
+	"github.com/marfebr/otel-lab/service-b/internal/hedge"
+	"github.com/marfebr/otel-lab/service-b/internal/httpclient"
+	"github.com/marfebr/otel-lab/service-b/internal/logging"
+	"github.com/marfebr/otel-lab/service-b/internal/peerauth"
+	"github.com/marfebr/otel-lab/service-b/internal/quota"
+	"github.com/marfebr/otel-lab/service-b/internal/shed"
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
+	"github.com/marfebr/otel-lab/service-b/internal/tlsconfig"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Config configuração tipada do serviço, carregada uma única vez na inicialização
+type Config struct {
+	HTTPPort string
+	// GRPCPort endereço do servidor gRPC (vazio desativa)
+	GRPCPort string
+	// TLS certificado do listener HTTP (vazio serve HTTP puro)
+	TLS TLSConfig
+	// LogLevel nível mínimo dos logs (debug, info, warn, error)
+	LogLevel string
+	// ViaCEPBaseURL URL base da API ViaCEP
+	ViaCEPBaseURL string
+	Weather       WeatherConfig
+	// UpstreamTimeout limite de cada chamada aos provedores externos
+	UpstreamTimeout time.Duration
+	// RequestTimeout prazo máximo de cada requisição, também usado sem X-Request-Timeout
+	RequestTimeout time.Duration
+	// CEPBudgetShare fração do tempo restante reservada à busca do CEP; o clima usa o resto
+	CEPBudgetShare float64
+	// MinUpstreamBudget tempo mínimo para chamar um provedor; abaixo disso a requisição falha
+	MinUpstreamBudget time.Duration
+	// HTTPClient pool de conexões e limites por fase das chamadas HTTP de saída
+	HTTPClient httpclient.Options
+	// Shed controle de admissão das requisições recebidas
+	Shed shed.Limits
+	// HealthCacheTTL tempo durante o qual o resultado do /readyz é reaproveitado
+	HealthCacheTTL time.Duration
+	Telemetry      telemetry.Config
+	DebugTraces    DebugTracesConfig
+	Fault          FaultConfig
+	Quotas         QuotaConfig
+	// Bulkheads chamadas simultâneas por dependência externa
+	Bulkheads BulkheadConfig
+	// Hedge segunda tentativa da busca de CEP quando a primeira demora
+	Hedge HedgeConfig
+	// ServiceAuth autenticação exigida nas chamadas ao POST /weather
+	ServiceAuth peerauth.Config
+	// HealthCredentialsTTL tempo durante o qual a verificação da chave da WeatherAPI é reaproveitada
+	HealthCredentialsTTL time.Duration
+
+	// file arquivo de configuração carregado (vazio se ausente)
+	file string
+	// values valores brutos de cada chave, usados para comparar recargas
+	values map[string]string
+}
+
+// WeatherConfig configuração do provedor de clima
+type WeatherConfig struct {
+	// Mode origem dos dados: live (WeatherAPI), mock ou fixture
+	Mode    string
+	BaseURL string
+	APIKey  string
+	// MockSeed semente das temperaturas geradas no modo mock
+	MockSeed int64
+	// FixtureFile arquivo com as respostas gravadas do modo fixture
+	FixtureFile string
+}
+
+// QuotaConfig cotas de chamadas de saída por provedor
+type QuotaConfig struct {
+	ViaCEP     quota.Limits
+	WeatherAPI quota.Limits
+}
+
+// BulkheadConfig limites de chamadas simultâneas por dependência
+type BulkheadConfig struct {
+	ViaCEP     bulkhead.Limits
+	WeatherAPI bulkhead.Limits
+}
+
+// HedgeConfig configuração da segunda tentativa (hedge) da busca de CEP
+type HedgeConfig struct {
+	Enabled bool
+	// SecondaryURL provedor compatível com o ViaCEP usado na segunda tentativa
+	// (vazio repete a chamada ao ViaCEP)
+	SecondaryURL string
+	Policy       hedge.Policy
+}
+
+// TLSConfig configuração TLS do listener HTTP
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// MinVersion versão mínima aceita no listener e nas conexões de saída
+	MinVersion uint16
+}
+
+// DebugTracesConfig configuração da página /debug/traces
+type DebugTracesConfig struct {
+	Enabled  bool
+	MaxSpans int
+}
+
+// FaultConfig configuração da injeção de falhas (desativada por padrão)
+type FaultConfig struct {
+	Enabled bool
+	// Rules falhas por alvo; a requisição pode trazer outras no header X-Fault-Inject
+	Rules fault.Rules
+}
+
+// ErrHelp retornado por Load quando --help é solicitado
+var ErrHelp = pflag.ErrHelp
+
+// setting chave de configuração com valor padrão e descrição da flag
+type setting struct {
+	key   string
+	value string
+	usage string
+}
+
+// settings chaves aceitas; cada uma pode vir do ambiente, do arquivo de configuração
+// ou da flag equivalente (ex.: HTTP_PORT → --http-port), nesta ordem de precedência:
+// flag, ambiente, arquivo, padrão
+var settings = []setting{
+	{"HTTP_PORT", ":8181", "HTTP listen address"},
+	{"GRPC_PORT", ":8282", "gRPC listen address of the WeatherService (empty disables)"},
+	{"HTTP_TLS_CERT_FILE", "", "TLS certificate of the HTTP listener (empty serves plain HTTP)"},
+	{"HTTP_TLS_KEY_FILE", "", "TLS private key of the HTTP listener"},
+	{"TLS_MIN_VERSION", "1.2", "minimum TLS version of the listener and outbound connections (1.2, 1.3)"},
+	{"LOG_LEVEL", "info", "minimum log level (debug, info, warn, error)"},
+	{"VIACEP_BASE_URL", "https://viacep.com.br/ws", "ViaCEP base URL"},
+	{"WEATHER_API_BASE_URL", "https://api.weatherapi.com/v1", "WeatherAPI base URL"},
+	{"WEATHER_API", "", "WeatherAPI key (required in live mode)"},
+	{"WEATHER_PROVIDER_MODE", "live", "weather data source (live, mock, fixture)"},
+	{"WEATHER_MOCK_SEED", "42", "seed of the deterministic temperatures of mock mode"},
+	{"WEATHER_FIXTURE_FILE", "", "JSON file with canned WeatherAPI responses by city (fixture mode)"},
+	{"UPSTREAM_TIMEOUT", "10s", "timeout of each call to ViaCEP and WeatherAPI"},
+	{"REQUEST_TIMEOUT", "10s", "maximum time per request, also used when the caller sends no X-Request-Timeout"},
+	{"UPSTREAM_BUDGET_CEP_SHARE", "0.5", "share of the remaining request time given to the ViaCEP call (the weather call gets the rest)"},
+	{"UPSTREAM_MIN_BUDGET", "10ms", "minimum time left to call a provider; below it the request fails with 504"},
+	{"HTTP_CLIENT_MAX_IDLE_CONNS", "100", "idle outbound connections kept across all hosts"},
+	{"HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", "20", "idle outbound connections kept per host"},
+	{"HTTP_CLIENT_MAX_CONNS_PER_HOST", "0", "concurrent outbound connections per host (0 disables)"},
+	{"HTTP_CLIENT_IDLE_CONN_TIMEOUT", "90s", "how long an idle outbound connection is kept"},
+	{"HTTP_CLIENT_DIAL_TIMEOUT", "3s", "timeout to open an outbound TCP connection"},
+	{"HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT", "5s", "timeout of the outbound TLS handshake"},
+	{"HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT", "10s", "timeout waiting for the response headers of an outbound call"},
+	{"SHED_MAX_IN_FLIGHT", "200", "requests served at the same time; health checks and metrics are always admitted (0 disables admission control)"},
+	{"SHED_MAX_QUEUE", "100", "requests that may wait for admission"},
+	{"SHED_MAX_QUEUE_TIME", "100ms", "how long a request may wait for admission before being shed with 503"},
+	{"SHED_LOW_PRIORITY_SHARE", "0.5", "share of SHED_MAX_IN_FLIGHT usable by low priority routes (/debug, /admin)"},
+	{"HEALTH_CACHE_TTL", "5s", "how long readiness results are cached"},
+	{"HEALTH_CREDENTIALS_TTL", "10m", "how long the readiness check of the WeatherAPI key is cached (each check is one WeatherAPI call)"},
+	{"OTEL_SERVICE_NAME", "service-b", "service name reported in telemetry"},
+	{"OTEL_EXPORTER_OTLP_ENDPOINT", "otel-collector:4317", "OTLP gRPC endpoint (host:port)"},
+	{"OTEL_EXPORTER_OTLP_HTTP_ENDPOINT", "otel-collector:4318", "OTLP HTTP endpoint (host:port)"},
+	{"OTEL_EXPORTER_OTLP_INSECURE", "true", "connect to the collector without TLS"},
+	{"OTEL_EXPORTER_OTLP_CERTIFICATE", "", "CA bundle trusted for the collector (empty uses the system CAs)"},
+	{"OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE", "", "client certificate presented to the collector"},
+	{"OTEL_EXPORTER_OTLP_CLIENT_KEY", "", "client private key presented to the collector"},
+	{"OTEL_EXPORTER_OTLP_SERVER_NAME", "", "expected server name in the collector certificate"},
+	{"OTEL_EXPORTER_ZIPKIN_ENDPOINT", "http://zipkin:9411/api/v2/spans", "Zipkin spans URL"},
+	{"OTEL_TRACES_EXPORTER", "otlp-grpc", "span exporters (otlp-grpc, otlp-http, zipkin, stdout, file, none)"},
+	{"OTEL_EXPORTER_FILE_DIR", "./telemetry", "directory of the file exporter"},
+	{"OTEL_EXPORTER_FILE_MAX_BYTES", "10485760", "file exporter rotation size in bytes (0 disables)"},
+	{"OTEL_EXPORTER_FILE_MAX_BACKUPS", "5", "rotated files kept by the file exporter"},
+	{"OTEL_EXPORTER_FILE_METRICS_INTERVAL", "30s", "interval between metric snapshots of the file exporter"},
+	{"OTEL_TRACES_SAMPLER", "parentbased_always_on", "trace sampler"},
+	{"OTEL_TRACES_SAMPLER_ARG", "", "sampler argument (ratio)"},
+	{"OTEL_TRACES_SAMPLER_KEEP_ERRORS", "false", "always keep spans with errors"},
+	{"OTEL_TRACES_SAMPLER_KEEP_LATENCY", "0s", "always keep spans slower than this (0 disables)"},
+	{"OTEL_PROPAGATORS", "tracecontext,baggage", "context propagators"},
+	{"BAGGAGE_SPAN_ATTRIBUTES", "client.id,tenant.id,api.key.id", "baggage keys copied to span attributes"},
+	{"SERVICE_AUTH_MODE", "none", "service-to-service authentication (none, mtls, hmac)"},
+	{"SERVICE_AUTH_SECRET", "", "shared HMAC secret of hmac mode (at least 32 characters)"},
+	{"SERVICE_AUTH_TOKEN_TTL", "30s", "maximum validity accepted for hmac tokens"},
+	{"SERVICE_AUTH_CA_FILE", "", "CA bundle that signs the service certificates (mtls mode)"},
+	{"SERVICE_AUTH_CERT_FILE", "", "server certificate (mtls mode)"},
+	{"SERVICE_AUTH_KEY_FILE", "", "server private key (mtls mode)"},
+	{"SERVICE_AUTH_ALLOWED_PEERS", "service-a", "accepted caller identities: certificate CN/SAN or token id (empty accepts any)"},
+	{"QUOTA_VIACEP_PER_SECOND", "5", "ViaCEP calls per second, fractional values allowed (0 disables)"},
+	{"QUOTA_VIACEP_PER_DAY", "0", "ViaCEP calls per UTC day (0 disables)"},
+	{"QUOTA_WEATHERAPI_PER_SECOND", "5", "WeatherAPI calls per second, fractional values allowed (0 disables)"},
+	{"QUOTA_WEATHERAPI_PER_DAY", "30000", "WeatherAPI calls per UTC day (0 disables)"},
+	{"QUOTA_MAX_WAIT", "250ms", "how long a call may queue for the per-second quota"},
+	{"BULKHEAD_VIACEP_MAX_CONCURRENT", "20", "concurrent ViaCEP calls (0 disables the limit)"},
+	{"BULKHEAD_WEATHERAPI_MAX_CONCURRENT", "20", "concurrent WeatherAPI calls (0 disables the limit)"},
+	{"BULKHEAD_MAX_QUEUE", "50", "calls that may wait for a slot per dependency (0 rejects as soon as the limit is reached)"},
+	{"BULKHEAD_QUEUE_TIMEOUT", "250ms", "how long a call may wait for a slot"},
+	{"BULKHEAD_ADAPTIVE", "false", "adjust the limits from observed latency (AIMD)"},
+	{"BULKHEAD_MIN_CONCURRENT", "2", "lowest limit in adaptive mode"},
+	{"BULKHEAD_LATENCY_TARGET", "1s", "calls slower than this shrink the limit in adaptive mode"},
+	{"HEDGE_ENABLED", "false", "fire a second CEP lookup when the first is slower than the observed percentile"},
+	{"HEDGE_PERCENTILE", "0.95", "percentile of recent CEP lookup latencies used as the hedge delay"},
+	{"HEDGE_MIN_DELAY", "50ms", "lower bound of the hedge delay"},
+	{"HEDGE_MAX_DELAY", "1s", "upper bound of the hedge delay, also used until enough latencies are observed"},
+	{"HEDGE_SECONDARY_URL", "", "ViaCEP-compatible base URL for the hedge attempt (empty repeats the call to VIACEP_BASE_URL)"},
+	{"DEBUG_TRACES_ENABLED", "false", "serve the /debug/traces pages"},
+	{"DEBUG_TRACES_MAX_SPANS", "1000", "finished spans kept for /debug/traces"},
+	{"FAULT_INJECTION_ENABLED", "false", "inject the faults of FAULT_RULES and of the X-Fault-Inject header (testing only)"},
+	{"FAULT_RULES", "", "faults per target as target:kind=value,... with kinds delay, error (rate) and status; targets are routes (/weather), gRPC methods and the viacep, viacep-secondary and weatherapi upstreams"},
+}
+
+// Load lê a configuração do ambiente, do arquivo indicado por --config (ou CONFIG_FILE)
+// e das flags em args e a valida. Valores mal formados e fora dos limites são
+// reportados juntos.
+func Load(args []string) (*Config, error) {
+	v := viper.New()
+	v.AutomaticEnv()
+
+	flags := pflag.NewFlagSet("service-b", pflag.ContinueOnError)
+	configFile := flags.String("config", "", "configuration file (yaml, json, toml or env); also CONFIG_FILE")
+	for _, s := range settings {
+		v.SetDefault(s.key, s.value)
+		flags.String(flagName(s.key), s.value, s.usage)
+		if err := v.BindPFlag(s.key, flags.Lookup(flagName(s.key))); err != nil {
+			return nil, err
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile == "" {
+		*configFile = v.GetString("CONFIG_FILE")
+	}
+	if *configFile != "" {
+		v.SetConfigFile(*configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", *configFile, err)
+		}
+	}
+
+	p := &parser{v: v}
+	minTLSVersion := p.tlsVersion("TLS_MIN_VERSION")
+	cfg := &Config{
+		HTTPPort: v.GetString("HTTP_PORT"),
+		GRPCPort: v.GetString("GRPC_PORT"),
+		TLS: TLSConfig{
+			CertFile:   v.GetString("HTTP_TLS_CERT_FILE"),
+			KeyFile:    v.GetString("HTTP_TLS_KEY_FILE"),
+			MinVersion: minTLSVersion,
+		},
+		LogLevel:      v.GetString("LOG_LEVEL"),
+		ViaCEPBaseURL: v.GetString("VIACEP_BASE_URL"),
+		Weather: WeatherConfig{
+			Mode:        strings.ToLower(v.GetString("WEATHER_PROVIDER_MODE")),
+			BaseURL:     v.GetString("WEATHER_API_BASE_URL"),
+			APIKey:      v.GetString("WEATHER_API"),
+			MockSeed:    int64(p.int("WEATHER_MOCK_SEED")),
+			FixtureFile: v.GetString("WEATHER_FIXTURE_FILE"),
+		},
+		UpstreamTimeout: p.duration("UPSTREAM_TIMEOUT"),
+		HealthCacheTTL:  p.duration("HEALTH_CACHE_TTL"),
+		HTTPClient: httpclient.Options{
+			MaxIdleConns:          p.int("HTTP_CLIENT_MAX_IDLE_CONNS"),
+			MaxIdleConnsPerHost:   p.int("HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST"),
+			MaxConnsPerHost:       p.int("HTTP_CLIENT_MAX_CONNS_PER_HOST"),
+			IdleConnTimeout:       p.duration("HTTP_CLIENT_IDLE_CONN_TIMEOUT"),
+			DialTimeout:           p.duration("HTTP_CLIENT_DIAL_TIMEOUT"),
+			TLSHandshakeTimeout:   p.duration("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT"),
+			ResponseHeaderTimeout: p.duration("HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT"),
+		},
+		Shed: shed.Limits{
+			MaxInFlight:      p.int("SHED_MAX_IN_FLIGHT"),
+			MaxQueue:         p.int("SHED_MAX_QUEUE"),
+			MaxQueueTime:     p.duration("SHED_MAX_QUEUE_TIME"),
+			LowPriorityShare: p.float("SHED_LOW_PRIORITY_SHARE"),
+		},
+		RequestTimeout:    p.duration("REQUEST_TIMEOUT"),
+		CEPBudgetShare:    p.float("UPSTREAM_BUDGET_CEP_SHARE"),
+		MinUpstreamBudget: p.duration("UPSTREAM_MIN_BUDGET"),
+		Telemetry: telemetry.Config{
+			ServiceName:      v.GetString("OTEL_SERVICE_NAME"),
+			Exporters:        telemetry.SplitList(strings.ToLower(v.GetString("OTEL_TRACES_EXPORTER"))),
+			OTLPGRPCEndpoint: v.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"),
+			OTLPHTTPEndpoint: v.GetString("OTEL_EXPORTER_OTLP_HTTP_ENDPOINT"),
+			ZipkinEndpoint:   v.GetString("OTEL_EXPORTER_ZIPKIN_ENDPOINT"),
+			File: telemetry.FileConfig{
+				Dir:             v.GetString("OTEL_EXPORTER_FILE_DIR"),
+				MaxBytes:        int64(p.int("OTEL_EXPORTER_FILE_MAX_BYTES")),
+				MaxBackups:      p.int("OTEL_EXPORTER_FILE_MAX_BACKUPS"),
+				MetricsInterval: p.duration("OTEL_EXPORTER_FILE_METRICS_INTERVAL"),
+			},
+			Sampler: telemetry.SamplerConfig{
+				Name:        v.GetString("OTEL_TRACES_SAMPLER"),
+				Arg:         v.GetString("OTEL_TRACES_SAMPLER_ARG"),
+				KeepErrors:  p.bool("OTEL_TRACES_SAMPLER_KEEP_ERRORS"),
+				KeepLatency: p.duration("OTEL_TRACES_SAMPLER_KEEP_LATENCY"),
+			},
+			Propagators: v.GetString("OTEL_PROPAGATORS"),
+			BaggageKeys: telemetry.SplitList(v.GetString("BAGGAGE_SPAN_ATTRIBUTES")),
+			OTLPTLS: telemetry.OTLPTLSConfig{
+				Insecure:   p.bool("OTEL_EXPORTER_OTLP_INSECURE"),
+				CAFile:     v.GetString("OTEL_EXPORTER_OTLP_CERTIFICATE"),
+				CertFile:   v.GetString("OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE"),
+				KeyFile:    v.GetString("OTEL_EXPORTER_OTLP_CLIENT_KEY"),
+				ServerName: v.GetString("OTEL_EXPORTER_OTLP_SERVER_NAME"),
+				MinVersion: minTLSVersion,
+			},
+		},
+		Bulkheads: BulkheadConfig{
+			ViaCEP:     p.bulkhead("BULKHEAD_VIACEP_MAX_CONCURRENT"),
+			WeatherAPI: p.bulkhead("BULKHEAD_WEATHERAPI_MAX_CONCURRENT"),
+		},
+		Hedge: HedgeConfig{
+			Enabled:      p.bool("HEDGE_ENABLED"),
+			SecondaryURL: v.GetString("HEDGE_SECONDARY_URL"),
+			Policy: hedge.Policy{
+				Percentile: p.float("HEDGE_PERCENTILE"),
+				MinDelay:   p.duration("HEDGE_MIN_DELAY"),
+				MaxDelay:   p.duration("HEDGE_MAX_DELAY"),
+			},
+		},
+		DebugTraces: DebugTracesConfig{
+			Enabled:  p.bool("DEBUG_TRACES_ENABLED"),
+			MaxSpans: p.int("DEBUG_TRACES_MAX_SPANS"),
+		},
+		Fault: FaultConfig{
+			Enabled: p.bool("FAULT_INJECTION_ENABLED"),
+			Rules:   p.faults("FAULT_RULES"),
+		},
+		Quotas: QuotaConfig{
+			ViaCEP: quota.Limits{
+				PerSecond: p.float("QUOTA_VIACEP_PER_SECOND"),
+				PerDay:    p.int("QUOTA_VIACEP_PER_DAY"),
+				MaxWait:   p.duration("QUOTA_MAX_WAIT"),
+			},
+			WeatherAPI: quota.Limits{
+				PerSecond: p.float("QUOTA_WEATHERAPI_PER_SECOND"),
+				PerDay:    p.int("QUOTA_WEATHERAPI_PER_DAY"),
+				MaxWait:   p.duration("QUOTA_MAX_WAIT"),
+			},
+		},
+		ServiceAuth: peerauth.Config{
+			Mode:         strings.ToLower(v.GetString("SERVICE_AUTH_MODE")),
+			Secret:       v.GetString("SERVICE_AUTH_SECRET"),
+			TokenTTL:     p.duration("SERVICE_AUTH_TOKEN_TTL"),
+			CAFile:       v.GetString("SERVICE_AUTH_CA_FILE"),
+			CertFile:     v.GetString("SERVICE_AUTH_CERT_FILE"),
+			KeyFile:      v.GetString("SERVICE_AUTH_KEY_FILE"),
+			MinVersion:   minTLSVersion,
+			AllowedPeers: telemetry.SplitList(v.GetString("SERVICE_AUTH_ALLOWED_PEERS")),
+		},
+		HealthCredentialsTTL: p.duration("HEALTH_CREDENTIALS_TTL"),
+
+		file:   *configFile,
+		values: make(map[string]string, len(settings)),
+	}
+	for _, s := range settings {
+		cfg.values[s.key] = v.GetString(s.key)
+	}
+
+	if err := errors.Join(p.errs...); err != nil {
+		return nil, errors.Join(err, cfg.Validate())
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate verifica endereços, portas e limites, reportando todos os problemas de uma vez
+func (c *Config) Validate() error {
+	var errs []error
+
+	errs = append(errs, validateListenAddr("HTTP_PORT", c.HTTPPort))
+	if c.GRPCPort != "" {
+		errs = append(errs, validateListenAddr("GRPC_PORT", c.GRPCPort))
+	}
+	errs = append(errs, validatePair("HTTP_TLS_CERT_FILE", c.TLS.CertFile, "HTTP_TLS_KEY_FILE", c.TLS.KeyFile))
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+	}
+	errs = append(errs, validateURL("VIACEP_BASE_URL", c.ViaCEPBaseURL))
+	errs = append(errs, validateWeather(c.Weather))
+	errs = append(errs, validateRange("UPSTREAM_TIMEOUT", c.UpstreamTimeout, time.Millisecond, 2*time.Minute))
+	errs = append(errs, validateRange("REQUEST_TIMEOUT", c.RequestTimeout, time.Millisecond, 5*time.Minute))
+	if c.CEPBudgetShare <= 0 || c.CEPBudgetShare >= 1 {
+		errs = append(errs, fmt.Errorf("UPSTREAM_BUDGET_CEP_SHARE: must be between 0 and 1 (exclusive), got %g", c.CEPBudgetShare))
+	}
+	errs = append(errs, validateRange("UPSTREAM_MIN_BUDGET", c.MinUpstreamBudget, 0, time.Second))
+	errs = append(errs, validateHTTPClient(c.HTTPClient))
+	errs = append(errs, validateShed(c.Shed))
+	errs = append(errs, validateRange("HEALTH_CACHE_TTL", c.HealthCacheTTL, 0, 5*time.Minute))
+	errs = append(errs, validateRange("HEALTH_CREDENTIALS_TTL", c.HealthCredentialsTTL, time.Minute, 24*time.Hour))
+	errs = append(errs, validateTelemetry(c.Telemetry))
+
+	errs = append(errs, validateServiceAuth(c.ServiceAuth))
+
+	errs = append(errs, validateQuota("QUOTA_VIACEP", c.Quotas.ViaCEP))
+	errs = append(errs, validateQuota("QUOTA_WEATHERAPI", c.Quotas.WeatherAPI))
+	errs = append(errs, validateRange("QUOTA_MAX_WAIT", c.Quotas.ViaCEP.MaxWait, 0, 10*time.Second))
+
+	errs = append(errs, validateBulkhead("BULKHEAD_VIACEP_MAX_CONCURRENT", c.Bulkheads.ViaCEP))
+	errs = append(errs, validateBulkhead("BULKHEAD_WEATHERAPI_MAX_CONCURRENT", c.Bulkheads.WeatherAPI))
+
+	if c.Hedge.Enabled {
+		errs = append(errs, validateHedge(c.Hedge))
+	}
+
+	if c.DebugTraces.Enabled && c.DebugTraces.MaxSpans <= 0 {
+		errs = append(errs, fmt.Errorf("DEBUG_TRACES_MAX_SPANS: must be positive, got %d", c.DebugTraces.MaxSpans))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateWeather exige a chave no modo live e o arquivo no modo fixture
+func validateWeather(cfg WeatherConfig) error {
+	switch cfg.Mode {
+	case "live":
+		if cfg.APIKey == "" {
+			return errors.New("WEATHER_API: required when WEATHER_PROVIDER_MODE=live (use mock or fixture to run without it)")
+		}
+		return validateURL("WEATHER_API_BASE_URL", cfg.BaseURL)
+	case "mock":
+		return nil
+	case "fixture":
+		if cfg.FixtureFile == "" {
+			return errors.New("WEATHER_FIXTURE_FILE: required when WEATHER_PROVIDER_MODE=fixture")
+		}
+		return nil
+	default:
+		return fmt.Errorf("WEATHER_PROVIDER_MODE: unknown mode %q (expected live, mock or fixture)", cfg.Mode)
+	}
+}
+
+// validateServiceAuth exige os certificados no modo mtls e o segredo no modo hmac
+func validateServiceAuth(cfg peerauth.Config) error {
+	switch cfg.Mode {
+	case peerauth.ModeNone:
+		return nil
+	case peerauth.ModeMTLS:
+		if cfg.CAFile == "" || cfg.CertFile == "" || cfg.KeyFile == "" {
+			return errors.New("SERVICE_AUTH_CA_FILE/SERVICE_AUTH_CERT_FILE/SERVICE_AUTH_KEY_FILE: required when SERVICE_AUTH_MODE=mtls")
+		}
+		return nil
+	case peerauth.ModeHMAC:
+		var errs []error
+		if len(cfg.Secret) < 32 {
+			errs = append(errs, errors.New("SERVICE_AUTH_SECRET: at least 32 characters required when SERVICE_AUTH_MODE=hmac"))
+		}
+		errs = append(errs, validateRange("SERVICE_AUTH_TOKEN_TTL", cfg.TokenTTL, time.Second, 10*time.Minute))
+		return errors.Join(errs...)
+	default:
+		return fmt.Errorf("SERVICE_AUTH_MODE: unknown mode %q (expected none, mtls or hmac)", cfg.Mode)
+	}
+}
+
+// validateBulkhead exige limites não negativos e, no modo adaptativo, mínimo entre 1 e o máximo
+func validateBulkhead(key string, limits bulkhead.Limits) error {
+	if limits.MaxConcurrent == 0 {
+		return nil
+	}
+	var errs []error
+	if limits.MaxConcurrent < 0 || limits.MaxQueue < 0 {
+		errs = append(errs, fmt.Errorf("%s/BULKHEAD_MAX_QUEUE: must not be negative", key))
+	}
+	errs = append(errs, validateRange("BULKHEAD_QUEUE_TIMEOUT", limits.QueueTimeout, 0, time.Minute))
+	if limits.Adaptive {
+		if limits.MinConcurrent < 1 || limits.MinConcurrent > limits.MaxConcurrent {
+			errs = append(errs, fmt.Errorf("BULKHEAD_MIN_CONCURRENT: must be between 1 and %s (%d), got %d", key, limits.MaxConcurrent, limits.MinConcurrent))
+		}
+		errs = append(errs, validateRange("BULKHEAD_LATENCY_TARGET", limits.LatencyTarget, time.Millisecond, time.Minute))
+	}
+	return errors.Join(errs...)
+}
+
+// validateHedge exige percentil entre 0 e 1 e atraso mínimo não maior que o máximo
+func validateHedge(cfg HedgeConfig) error {
+	var errs []error
+	if cfg.Policy.Percentile <= 0 || cfg.Policy.Percentile >= 1 {
+		errs = append(errs, fmt.Errorf("HEDGE_PERCENTILE: must be between 0 and 1 (exclusive), got %g", cfg.Policy.Percentile))
+	}
+	errs = append(errs, validateRange("HEDGE_MAX_DELAY", cfg.Policy.MaxDelay, time.Millisecond, time.Minute))
+	errs = append(errs, validateRange("HEDGE_MIN_DELAY", cfg.Policy.MinDelay, 0, cfg.Policy.MaxDelay))
+	if cfg.SecondaryURL != "" {
+		errs = append(errs, validateURL("HEDGE_SECONDARY_URL", cfg.SecondaryURL))
+	}
+	return errors.Join(errs...)
+}
+
+// validateQuota exige cotas não negativas (zero desativa a janela)
+func validateQuota(prefix string, limits quota.Limits) error {
+	if limits.PerSecond < 0 || limits.PerDay < 0 {
+		return fmt.Errorf("%s_PER_SECOND/%s_PER_DAY: must not be negative", prefix, prefix)
+	}
+	return nil
+}
+
+// validateHTTPClient exige tamanhos de pool não negativos e limites de tempo positivos
+func validateHTTPClient(opts httpclient.Options) error {
+	var errs []error
+	if opts.MaxIdleConns < 0 || opts.MaxIdleConnsPerHost < 0 || opts.MaxConnsPerHost < 0 {
+		errs = append(errs, errors.New("HTTP_CLIENT_MAX_IDLE_CONNS/HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST/HTTP_CLIENT_MAX_CONNS_PER_HOST: must not be negative"))
+	}
+	errs = append(errs, validateRange("HTTP_CLIENT_IDLE_CONN_TIMEOUT", opts.IdleConnTimeout, time.Second, 10*time.Minute))
+	errs = append(errs, validateRange("HTTP_CLIENT_DIAL_TIMEOUT", opts.DialTimeout, time.Millisecond, time.Minute))
+	errs = append(errs, validateRange("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT", opts.TLSHandshakeTimeout, time.Millisecond, time.Minute))
+	errs = append(errs, validateRange("HTTP_CLIENT_RESPONSE_HEADER_TIMEOUT", opts.ResponseHeaderTimeout, time.Millisecond, 5*time.Minute))
+	return errors.Join(errs...)
+}
+
+// validateShed exige limites não negativos e fração de baixa prioridade entre 0 e 1
+func validateShed(limits shed.Limits) error {
+	var errs []error
+	if limits.MaxInFlight < 0 || limits.MaxQueue < 0 {
+		errs = append(errs, errors.New("SHED_MAX_IN_FLIGHT/SHED_MAX_QUEUE: must not be negative"))
+	}
+	errs = append(errs, validateRange("SHED_MAX_QUEUE_TIME", limits.MaxQueueTime, 0, time.Minute))
+	if limits.LowPriorityShare < 0 || limits.LowPriorityShare > 1 {
+		errs = append(errs, fmt.Errorf("SHED_LOW_PRIORITY_SHARE: must be between 0 and 1, got %g", limits.LowPriorityShare))
+	}
+	return errors.Join(errs...)
+}
+
+// validateTelemetry valida apenas os endpoints dos exportadores selecionados
+func validateTelemetry(cfg telemetry.Config) error {
+	var errs []error
+
+	for _, exporter := range cfg.Exporters {
+		switch exporter {
+		case telemetry.ExporterOTLPGRPC, "otlp":
+			errs = append(errs, validateHostPort("OTEL_EXPORTER_OTLP_ENDPOINT", cfg.OTLPGRPCEndpoint))
+		case telemetry.ExporterOTLPHTTP:
+			errs = append(errs, validateHostPort("OTEL_EXPORTER_OTLP_HTTP_ENDPOINT", cfg.OTLPHTTPEndpoint))
+		case telemetry.ExporterZipkin:
+			errs = append(errs, validateURL("OTEL_EXPORTER_ZIPKIN_ENDPOINT", cfg.ZipkinEndpoint))
+		case telemetry.ExporterFile:
+			if cfg.File.Dir == "" {
+				errs = append(errs, errors.New("OTEL_EXPORTER_FILE_DIR: must not be empty"))
+			}
+			if cfg.File.MaxBytes < 0 || cfg.File.MaxBackups < 0 {
+				errs = append(errs, errors.New("OTEL_EXPORTER_FILE_MAX_BYTES/OTEL_EXPORTER_FILE_MAX_BACKUPS: must not be negative"))
+			}
+			errs = append(errs, validateRange("OTEL_EXPORTER_FILE_METRICS_INTERVAL", cfg.File.MetricsInterval, time.Second, time.Hour))
+		}
+	}
+	if !cfg.OTLPTLS.Insecure {
+		errs = append(errs, validatePair("OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE", cfg.OTLPTLS.CertFile, "OTEL_EXPORTER_OTLP_CLIENT_KEY", cfg.OTLPTLS.KeyFile))
+	}
+	if err := telemetry.ValidateExporters(cfg.Exporters); err != nil {
+		errs = append(errs, fmt.Errorf("OTEL_TRACES_EXPORTER: %w", err))
+	}
+	if _, err := telemetry.NewPropagator(cfg.Propagators); err != nil {
+		errs = append(errs, fmt.Errorf("OTEL_PROPAGATORS: %w", err))
+	}
+	if _, err := telemetry.NewSampler(cfg.Sampler.Name, cfg.Sampler.Arg); err != nil {
+		errs = append(errs, fmt.Errorf("OTEL_TRACES_SAMPLER/OTEL_TRACES_SAMPLER_ARG: %w", err))
+	}
+	errs = append(errs, validateRange("OTEL_TRACES_SAMPLER_KEEP_LATENCY", cfg.Sampler.KeepLatency, 0, time.Minute))
+
+	return errors.Join(errs...)
+}
+
+// validateListenAddr aceita "host:porta" ou ":porta"
+func validateListenAddr(key, addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%s: invalid listen address %q (expected [host]:port)", key, addr)
+	}
+	return validatePort(key, port)
+}
+
+// validateHostPort exige host e porta (ex.: otel-collector:4317)
+func validateHostPort(key, addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return fmt.Errorf("%s: invalid endpoint %q (expected host:port)", key, addr)
+	}
+	return validatePort(key, port)
+}
+
+func validatePort(key, port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%s: invalid port %q (expected 1-65535)", key, port)
+	}
+	return nil
+}
+
+// validateURL exige URL absoluta http(s) com host
+func validateURL(key, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s: invalid URL %q (expected http(s)://host[:port][/path])", key, raw)
+	}
+	if u.Port() != "" {
+		return validatePort(key, u.Port())
+	}
+	return nil
+}
+
+// validatePair exige que os dois valores (ex.: certificado e chave) sejam informados juntos
+func validatePair(key1, value1, key2, value2 string) error {
+	if (value1 == "") != (value2 == "") {
+		return fmt.Errorf("%s/%s: both must be set together", key1, key2)
+	}
+	return nil
+}
+
+func validateRange(key string, d, min, max time.Duration) error {
+	if d < min || d > max {
+		return fmt.Errorf("%s: %s out of range [%s, %s]", key, d, min, max)
+	}
+	return nil
+}
+
+// flagName converte a chave para o nome da flag (HTTP_PORT → http-port)
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// parser converte valores acumulando os erros de formato
+type parser struct {
+	v    *viper.Viper
+	errs []error
+}
+
+func (p *parser) duration(key string) time.Duration {
+	d, err := time.ParseDuration(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid duration %q", key, p.v.GetString(key)))
+	}
+	return d
+}
+
+func (p *parser) float(key string) float64 {
+	f, err := strconv.ParseFloat(p.v.GetString(key), 64)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid number %q", key, p.v.GetString(key)))
+	}
+	return f
+}
+
+// bulkhead monta os limites da dependência; fila e modo adaptativo são comuns a todas
+func (p *parser) bulkhead(maxKey string) bulkhead.Limits {
+	return bulkhead.Limits{
+		MaxConcurrent: p.int(maxKey),
+		MaxQueue:      p.int("BULKHEAD_MAX_QUEUE"),
+		QueueTimeout:  p.duration("BULKHEAD_QUEUE_TIMEOUT"),
+		Adaptive:      p.bool("BULKHEAD_ADAPTIVE"),
+		MinConcurrent: p.int("BULKHEAD_MIN_CONCURRENT"),
+		LatencyTarget: p.duration("BULKHEAD_LATENCY_TARGET"),
+	}
+}
+
+func (p *parser) faults(key string) fault.Rules {
+	rules, err := fault.ParseRules(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: %w", key, err))
+	}
+	return rules
+}
+
+func (p *parser) int(key string) int {
+	n, err := strconv.Atoi(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid integer %q", key, p.v.GetString(key)))
+	}
+	return n
+}
+
+func (p *parser) tlsVersion(key string) uint16 {
+	version, err := tlsconfig.ParseVersion(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: %w", key, err))
+	}
+	return version
+}
+
+func (p *parser) bool(key string) bool {
+	b, err := strconv.ParseBool(p.v.GetString(key))
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s: invalid boolean %q", key, p.v.GetString(key)))
+	}
+	return b
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// reloadable chaves aplicadas em execução; alterações nas demais exigem reinício
+var reloadable = map[string]bool{
+	"LOG_LEVEL":               true,
+	"OTEL_TRACES_SAMPLER":     true,
+	"OTEL_TRACES_SAMPLER_ARG": true,
+	"HEALTH_CACHE_TTL":        true,
+	"HEALTH_CREDENTIALS_TTL":  true,
+	"FAULT_RULES":             true,
+
+	"QUOTA_VIACEP_PER_SECOND":     true,
+	"QUOTA_VIACEP_PER_DAY":        true,
+	"QUOTA_WEATHERAPI_PER_SECOND": true,
+	"QUOTA_WEATHERAPI_PER_DAY":    true,
+	"QUOTA_MAX_WAIT":              true,
+}
+
+// secrets chaves cujo valor não aparece nos logs de recarga
+var secrets = map[string]bool{
+	"SERVICE_AUTH_SECRET": true,
+	"WEATHER_API":         true,
+}
+
+// applyReloadable copia para dst os campos das chaves recarregáveis de src
+func applyReloadable(dst, src *Config) {
+	dst.LogLevel = src.LogLevel
+	dst.Telemetry.Sampler.Name = src.Telemetry.Sampler.Name
+	dst.Telemetry.Sampler.Arg = src.Telemetry.Sampler.Arg
+	dst.HealthCacheTTL = src.HealthCacheTTL
+	dst.HealthCredentialsTTL = src.HealthCredentialsTTL
+	dst.Fault.Rules = src.Fault.Rules
+	dst.Quotas = src.Quotas
+}
+
+// configReloads contador de recargas por gatilho e resultado
+var configReloads = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "config_reloads_total",
+	Help: "Configuration reloads by trigger (file, sighup) and result (applied, rejected, unchanged).",
+}, []string{"trigger", "result"})
+
+// Change alteração de uma chave entre duas configurações
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+// String formata a alteração ocultando valores sensíveis
+func (c Change) String() string {
+	if secrets[c.Key] {
+		return c.Key + ": <redacted>"
+	}
+	return fmt.Sprintf("%s: %q -> %q", c.Key, c.Old, c.New)
+}
+
+// Diff lista as chaves com valores diferentes, em ordem alfabética
+func Diff(old, new *Config) []Change {
+	var changes []Change
+	for key, value := range new.values {
+		if old.values[key] != value {
+			changes = append(changes, Change{Key: key, Old: old.values[key], New: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// Watcher recarrega a configuração quando o arquivo muda ou o processo recebe SIGHUP.
+// Apenas as chaves recarregáveis são aplicadas, todas juntas, e somente se a nova
+// configuração for válida.
+type Watcher struct {
+	args []string
+
+	mu      sync.Mutex
+	current *Config
+	hooks   []func(*Config)
+}
+
+// NewWatcher cria um Watcher a partir da configuração inicial e dos argumentos usados em Load
+func NewWatcher(cfg *Config, args []string) *Watcher {
+	return &Watcher{args: args, current: cfg}
+}
+
+// OnReload registra uma função chamada com a configuração em vigor após cada recarga aplicada
+func (w *Watcher) OnReload(fn func(*Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.hooks = append(w.hooks, fn)
+}
+
+// Current retorna a configuração em vigor
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Start observa o SIGHUP e, se houver arquivo de configuração, suas alterações,
+// até o contexto ser cancelado
+func (w *Watcher) Start(ctx context.Context) error {
+	var events <-chan fsnotify.Event
+	if w.current.file != "" {
+		fw, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("failed to watch config file: %w", err)
+		}
+		// Observar o diretório cobre editores que substituem o arquivo e ConfigMaps (symlinks)
+		if err := fw.Add(filepath.Dir(w.current.file)); err != nil {
+			fw.Close()
+			return fmt.Errorf("failed to watch config file: %w", err)
+		}
+		go func() {
+			<-ctx.Done()
+			fw.Close()
+		}()
+		events = fw.Events
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sighup)
+
+		// Agrupar a rajada de eventos de uma única gravação
+		debounce := time.NewTimer(time.Hour)
+		debounce.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sighup:
+				w.Reload("sighup")
+			case _, ok := <-events:
+				if !ok {
+					events = nil
+					continue
+				}
+				debounce.Reset(200 * time.Millisecond)
+			case <-debounce.C:
+				w.Reload("file")
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Reload relê a configuração e aplica as chaves recarregáveis, registrando o diff
+func (w *Watcher) Reload(trigger string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next, err := Load(w.args)
+	if err != nil {
+		configReloads.WithLabelValues(trigger, "rejected").Inc()
+		slog.Error("config reload rejected, keeping current configuration", "trigger", trigger, "error", err)
+		return
+	}
+
+	var applied, pending []string
+	effective := *w.current
+	effective.values = maps.Clone(w.current.values)
+	for _, change := range Diff(w.current, next) {
+		if !reloadable[change.Key] {
+			pending = append(pending, change.String())
+			continue
+		}
+		applied = append(applied, change.String())
+		effective.values[change.Key] = change.New
+	}
+
+	if len(pending) > 0 {
+		slog.Warn("config changes require a restart and were not applied", "trigger", trigger, "changes", pending)
+	}
+	if len(applied) == 0 {
+		configReloads.WithLabelValues(trigger, "unchanged").Inc()
+		return
+	}
+
+	applyReloadable(&effective, next)
+	w.current = &effective
+	for _, hook := range w.hooks {
+		hook(w.current)
+	}
+
+	configReloads.WithLabelValues(trigger, "applied").Inc()
+	slog.Info("config reloaded", "trigger", trigger, "changes", applied)
+}
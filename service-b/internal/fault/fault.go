@@ -0,0 +1,302 @@
+// Package fault injeta falhas controladas (atraso e erros) nas rotas e nas chamadas
+// de saída, para observar nos traces como os serviços se comportam quando algo
+// falha. As regras vêm da configuração ou, por requisição, do header X-Fault-Inject,
+// que segue para o serviço seguinte. Toda falha injetada é marcada no span.
+package fault
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-b/internal/deadline"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Header regras de falha da requisição, no mesmo formato de FAULT_RULES
+const Header = "X-Fault-Inject"
+
+// metadataKey equivalente do header nas chamadas gRPC
+const metadataKey = "x-fault-inject"
+
+// ErrInjected erro das falhas injetadas
+var ErrInjected = errors.New("injected fault")
+
+// Atributos dos spans com falha injetada
+const (
+	injectedKey = attribute.Key("fault.injected")
+	targetKey   = attribute.Key("fault.target")
+	typeKey     = attribute.Key("fault.type")
+	delayKey    = attribute.Key("fault.delay_ms")
+	errorKey    = attribute.Key("fault.error")
+)
+
+// faultsInjected contador de falhas injetadas por alvo e tipo
+var faultsInjected = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "faults_injected_total",
+	Help: "Injected faults by target (route, gRPC method or upstream) and type (delay, error).",
+}, []string{"target", "type"})
+
+// Fault falha de um alvo
+type Fault struct {
+	// Delay atraso adicionado antes de atender a rota ou fazer a chamada
+	Delay time.Duration
+	// ErrorRate probabilidade (0 a 1) de falhar
+	ErrorRate float64
+	// Status status HTTP das falhas injetadas em rotas (padrão 500)
+	Status int
+}
+
+// Rules falhas por alvo: rota (ex.: /cep), método gRPC ou upstream (ex.: viacep)
+type Rules map[string]Fault
+
+// ParseRules lê regras no formato alvo:tipo=valor,... com os tipos delay (duração),
+// error (probabilidade) e status (400 a 599), ex.: /cep:delay=200ms,viacep:error=0.5
+func ParseRules(spec string) (Rules, error) {
+	rules := make(Rules)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		sep := strings.LastIndex(name, ":")
+		if !ok || sep <= 0 {
+			return nil, fmt.Errorf("invalid fault rule %q (expected target:kind=value)", entry)
+		}
+		target, kind := name[:sep], name[sep+1:]
+
+		f := rules[target]
+		var err error
+		switch kind {
+		case "delay":
+			f.Delay, err = time.ParseDuration(value)
+			if err == nil && f.Delay < 0 {
+				err = errors.New("must not be negative")
+			}
+		case "error":
+			f.ErrorRate, err = strconv.ParseFloat(value, 64)
+			if err == nil && (f.ErrorRate < 0 || f.ErrorRate > 1) {
+				err = errors.New("must be between 0 and 1")
+			}
+		case "status":
+			f.Status, err = strconv.Atoi(value)
+			if err == nil && (f.Status < 400 || f.Status > 599) {
+				err = errors.New("must be between 400 and 599")
+			}
+		default:
+			err = errors.New("unknown kind (delay, error, status)")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid fault rule %q: %w", entry, err)
+		}
+		rules[target] = f
+	}
+	return rules, nil
+}
+
+// requestKey chave das regras do header no contexto
+type requestKey struct{}
+
+// requested regras do header da requisição e o valor original, repassado adiante
+type requested struct {
+	raw   string
+	rules Rules
+}
+
+// Injector aplica as regras da configuração e as do header da requisição, que
+// prevalecem para o mesmo alvo. Um Injector nil não injeta falhas nem lê o header.
+type Injector struct {
+	rules atomic.Pointer[Rules]
+}
+
+// NewInjector cria o Injector com as regras da configuração
+func NewInjector(rules Rules) *Injector {
+	i := &Injector{}
+	i.SetRules(rules)
+	return i
+}
+
+// SetRules troca as regras da configuração (recarga de configuração)
+func (i *Injector) SetRules(rules Rules) {
+	if i == nil {
+		return
+	}
+	i.rules.Store(&rules)
+}
+
+// Middleware lê o header X-Fault-Inject (inválido recebe 400) e aplica a falha da
+// rota; o erro injetado responde com o status da regra sem chegar ao handler
+func (i *Injector) Middleware(next http.Handler) http.Handler {
+	if i == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if raw := r.Header.Get(Header); raw != "" {
+			rules, err := ParseRules(raw)
+			if err != nil {
+				writeError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ctx = context.WithValue(ctx, requestKey{}, &requested{raw: raw, rules: rules})
+			r = r.WithContext(ctx)
+		}
+
+		f, err := i.apply(ctx, r.URL.Path)
+		switch {
+		case errors.Is(err, ErrInjected):
+			writeError(w, ErrInjected.Error(), cmp.Or(f.Status, http.StatusInternalServerError))
+			return
+		case err != nil:
+			writeError(w, deadline.ErrExceeded.Error(), http.StatusGatewayTimeout)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Transport aplica a falha do upstream a cada chamada feita por base; o erro
+// injetado é retornado no lugar da resposta
+func (i *Injector) Transport(upstream string, base http.RoundTripper) http.RoundTripper {
+	if i == nil {
+		return base
+	}
+	return &transport{injector: i, upstream: upstream, base: base}
+}
+
+type transport struct {
+	injector *Injector
+	upstream string
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, err := t.injector.apply(req.Context(), t.upstream); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// UnaryServerInterceptor lê as regras da metadata (inválidas recebem
+// InvalidArgument) e aplica a falha do método; o erro injetado vira Unavailable
+func (i *Injector) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if i == nil {
+		return handler(ctx, req)
+	}
+	ctx, err := i.applyRPC(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor equivalente do UnaryServerInterceptor para chamadas com
+// stream; a falha é aplicada antes do handler
+func (i *Injector) StreamServerInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if i == nil {
+		return handler(srv, ss)
+	}
+	ctx, err := i.applyRPC(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// applyRPC guarda no contexto as regras da metadata e aplica a falha do método,
+// convertendo os erros em status gRPC
+func (i *Injector) applyRPC(ctx context.Context, fullMethod string) (context.Context, error) {
+	if values := metadata.ValueFromIncomingContext(ctx, metadataKey); len(values) > 0 {
+		rules, err := ParseRules(values[0])
+		if err != nil {
+			return ctx, status.Error(codes.InvalidArgument, err.Error())
+		}
+		ctx = context.WithValue(ctx, requestKey{}, &requested{raw: values[0], rules: rules})
+	}
+
+	if _, err := i.apply(ctx, fullMethod); err != nil {
+		if errors.Is(err, ErrInjected) {
+			return ctx, status.Error(codes.Unavailable, ErrInjected.Error())
+		}
+		return ctx, status.FromContextError(err).Err()
+	}
+	return ctx, nil
+}
+
+// serverStream stream com o contexto que carrega as regras da metadata
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// rule falha do alvo: a do header da requisição ou, sem ela, a da configuração
+func (i *Injector) rule(ctx context.Context, target string) (Fault, bool) {
+	if inject, ok := ctx.Value(requestKey{}).(*requested); ok {
+		if f, ok := inject.rules[target]; ok {
+			return f, true
+		}
+	}
+	f, ok := (*i.rules.Load())[target]
+	return f, ok
+}
+
+// apply espera o atraso e sorteia o erro da regra do alvo. Retorna ErrInjected
+// quando o erro é sorteado ou o erro do contexto se ele terminar durante o atraso.
+func (i *Injector) apply(ctx context.Context, target string) (Fault, error) {
+	f, ok := i.rule(ctx, target)
+	if !ok {
+		return f, nil
+	}
+	span := trace.SpanFromContext(ctx)
+
+	if f.Delay > 0 {
+		record(span, target, "delay", delayKey.Int64(f.Delay.Milliseconds()))
+		timer := time.NewTimer(f.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return f, ctx.Err()
+		}
+	}
+	if f.ErrorRate > 0 && rand.Float64() < f.ErrorRate {
+		record(span, target, "error", errorKey.Bool(true))
+		return f, fmt.Errorf("%w: %s", ErrInjected, target)
+	}
+	return f, nil
+}
+
+// record conta a falha e a marca no span: fault.injected, o alvo e o detalhe da
+// falha como atributos, e um evento por falha com o tipo
+func record(span trace.Span, target, kind string, detail attribute.KeyValue) {
+	faultsInjected.WithLabelValues(target, kind).Inc()
+	span.SetAttributes(injectedKey.Bool(true), targetKey.String(target), detail)
+	span.AddEvent("fault injected", trace.WithAttributes(targetKey.String(target), typeKey.String(kind), detail))
+}
+
+// writeError responde no mesmo formato de erro dos handlers
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -1,13 +1,19 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
+	"github.com/marfebr/otel-lab/service-b/internal/bulkhead"
+	"github.com/marfebr/otel-lab/service-b/internal/deadline"
+	"github.com/marfebr/otel-lab/service-b/internal/quota"
 	"github.com/marfebr/otel-lab/service-b/internal/service"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -27,8 +33,7 @@ func NewWeatherHandler(weatherOrchestrator *service.WeatherOrchestrator, tracer
 
 // HandleWeatherRequest processa a requisição de dados de clima
 func (h *WeatherHandler) HandleWeatherRequest(w http.ResponseWriter, r *http.Request) {
-	// Extrair contexto OTEL do header HTTP
-	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx := r.Context()
 
 	// Criar span para tracing
 	ctx, span := h.tracer.Start(ctx, "weather-request")
@@ -43,7 +48,7 @@ func (h *WeatherHandler) HandleWeatherRequest(w http.ResponseWriter, r *http.Req
 	// Decodificar request
 	var req service.CEPRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
@@ -52,14 +57,32 @@ func (h *WeatherHandler) HandleWeatherRequest(w http.ResponseWriter, r *http.Req
 	// Buscar dados de clima
 	weatherResp, err := h.weatherOrchestrator.GetWeatherByCEP(ctx, req.CEP)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		log.Printf("Erro retornado por GetWeatherByCEP: %v", err)
+		// Cota de saída esgotada: o cliente pode tentar novamente mais tarde
+		var exhausted *quota.ExhaustedError
+		if errors.As(err, &exhausted) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(exhausted.RetryAfter.Seconds()))))
+			h.sendErrorResponse(w, quota.ErrQuotaExhausted.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		// Limite de chamadas simultâneas ao provedor atingido: falha rápida
+		if errors.Is(err, bulkhead.ErrRejected) {
+			w.Header().Set("Retry-After", "1")
+			h.sendErrorResponse(w, bulkhead.ErrRejected.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		// Prazo da requisição esgotado antes de concluir as chamadas aos provedores
+		if errors.Is(err, context.DeadlineExceeded) {
+			h.sendErrorResponse(w, deadline.ErrExceeded.Error(), http.StatusGatewayTimeout)
+			return
+		}
 		// Verificar tipo de erro e retornar status code apropriado
 		switch err {
 		case service.ErrInvalidCEP:
 			h.sendErrorResponse(w, err.Error(), http.StatusUnprocessableEntity)
 			return
-		case service.ErrCEPNotFound:
+		case service.ErrCEPNotFound, service.ErrCityNotFound:
 			h.sendErrorResponse(w, err.Error(), http.StatusNotFound)
 			return
 		default:
@@ -69,6 +92,7 @@ func (h *WeatherHandler) HandleWeatherRequest(w http.ResponseWriter, r *http.Req
 	}
 
 	// Retornar dados de clima
+	w.Header().Set(service.WeatherSourceHeader, weatherResp.Source)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(weatherResp)
@@ -0,0 +1,210 @@
+// Package hedge reduz a latência de cauda de chamadas idempotentes: se a primeira
+// tentativa não responde dentro do atraso calculado a partir do percentil observado,
+// uma segunda tentativa é disparada e a primeira que tiver sucesso vence
+package hedge
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Atributos dos spans de tentativa e do span da operação
+const (
+	attemptKey = attribute.Key("hedge.attempt")
+	outcomeKey = attribute.Key("hedge.outcome")
+	firedKey   = attribute.Key("hedge.fired")
+	delayKey   = attribute.Key("hedge.delay_ms")
+)
+
+// Resultados de cada tentativa
+const (
+	outcomeWon       = "won"
+	outcomeCancelled = "cancelled"
+	outcomeFailed    = "failed"
+)
+
+// minSamples latências necessárias antes de usar o percentil; maxSamples janela mantida
+const (
+	minSamples = 20
+	maxSamples = 256
+)
+
+var (
+	// hedgedRequests chamadas em que a segunda tentativa foi disparada, por vencedora
+	hedgedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "hedged_requests_total",
+		Help: "Calls that fired a hedge attempt, by operation and winning attempt (primary, hedge, none).",
+	}, []string{"operation", "winner"})
+
+	// hedgeDelay atraso em vigor antes de disparar a segunda tentativa
+	hedgeDelay = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hedge_delay_seconds",
+		Help: "Current delay before a hedge attempt is fired, by operation.",
+	}, []string{"operation"})
+)
+
+// Policy define o atraso da segunda tentativa: o percentil das latências recentes,
+// limitado a [MinDelay, MaxDelay]. Até haver amostras suficientes usa MaxDelay.
+type Policy struct {
+	Percentile float64
+	MinDelay   time.Duration
+	MaxDelay   time.Duration
+}
+
+// Hedger mantém as latências recentes de uma operação e dispara as segundas tentativas
+type Hedger struct {
+	operation string
+	policy    Policy
+	tracer    trace.Tracer
+
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// NewHedger cria o Hedger da operação (usada nos nomes dos spans e nas métricas)
+func NewHedger(operation string, policy Policy, tracer trace.Tracer) *Hedger {
+	hedgeDelay.WithLabelValues(operation).Set(policy.MaxDelay.Seconds())
+	return &Hedger{
+		operation: operation,
+		policy:    policy,
+		tracer:    tracer,
+		samples:   make([]time.Duration, 0, maxSamples),
+	}
+}
+
+// Delay atraso atual antes de disparar a segunda tentativa
+func (h *Hedger) Delay() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < minSamples {
+		return h.policy.MaxDelay
+	}
+	sorted := slices.Clone(h.samples)
+	slices.Sort(sorted)
+	delay := sorted[int(float64(len(sorted)-1)*h.policy.Percentile)]
+	return min(max(delay, h.policy.MinDelay), h.policy.MaxDelay)
+}
+
+// observe registra a latência de uma tentativa, substituindo a mais antiga
+func (h *Hedger) observe(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < maxSamples {
+		h.samples = append(h.samples, latency)
+		return
+	}
+	h.samples[h.next] = latency
+	h.next = (h.next + 1) % maxSamples
+}
+
+// attempt tentativa em curso ou concluída
+type attempt[T any] struct {
+	name   string
+	value  T
+	err    error
+	span   trace.Span
+	cancel context.CancelFunc
+}
+
+// Do executa fn e, se não houver resposta dentro de Delay, uma segunda vez com
+// hedge=true (que pode ir a outro provedor). Vence a primeira tentativa com sucesso
+// e a outra é cancelada. Um erro da primeira antes do atraso é retornado sem nova
+// tentativa; se as duas falharem, retorna o erro da primeira.
+//
+// Cada chamada com sucesso entra nas latências observadas uma única vez, medida desde
+// o início da primeira tentativa: quando a segunda vence, o valor é o mínimo que a
+// primeira levaria, e não a latência curta da segunda, que puxaria o percentil para
+// baixo.
+func Do[T any](ctx context.Context, h *Hedger, fn func(ctx context.Context, hedge bool) (T, error)) (T, error) {
+	delay := h.Delay()
+	hedgeDelay.WithLabelValues(h.operation).Set(delay.Seconds())
+	parent := trace.SpanFromContext(ctx)
+	parent.SetAttributes(delayKey.Int64(delay.Milliseconds()), firedKey.Bool(false))
+
+	done := make(chan *attempt[T], 2)
+	launch := func(name string, hedge bool) *attempt[T] {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		attemptCtx, span := h.tracer.Start(attemptCtx, h.operation+"-attempt", trace.WithAttributes(attemptKey.String(name)))
+		a := &attempt[T]{name: name, span: span, cancel: cancel}
+		go func() {
+			a.value, a.err = fn(attemptCtx, hedge)
+			done <- a
+		}()
+		return a
+	}
+
+	start := time.Now()
+	primary := launch("primary", false)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case a := <-done:
+		if a.err != nil {
+			return finish(a, outcomeFailed)
+		}
+		h.observe(time.Since(start))
+		return finish(a, outcomeWon)
+	case <-timer.C:
+	}
+	if ctx.Err() != nil {
+		// Sem prazo para uma nova tentativa; a primeira termina com o erro do contexto
+		return finish(<-done, outcomeFailed)
+	}
+
+	hedge := launch("hedge", true)
+	parent.SetAttributes(firedKey.Bool(true))
+
+	// Duas tentativas em curso: a primeira com sucesso vence e cancela a outra
+	var failed *attempt[T]
+	for pending := 2; pending > 0; pending-- {
+		a := <-done
+		if a.err != nil {
+			finish(a, outcomeFailed)
+			if failed == nil || a == primary {
+				failed = a
+			}
+			continue
+		}
+
+		h.observe(time.Since(start))
+		hedgedRequests.WithLabelValues(h.operation, a.name).Inc()
+		if pending == 2 {
+			// A perdedora é cancelada e seu span encerrado quando ela retornar
+			loser := primary
+			if a == primary {
+				loser = hedge
+			}
+			loser.cancel()
+			go func() { finish(<-done, outcomeCancelled) }()
+		}
+		return finish(a, outcomeWon)
+	}
+
+	hedgedRequests.WithLabelValues(h.operation, "none").Inc()
+	return failed.value, failed.err
+}
+
+// finish libera o contexto da tentativa e encerra seu span
+func finish[T any](a *attempt[T], outcome string) (T, error) {
+	a.cancel()
+
+	a.span.SetAttributes(outcomeKey.String(outcome))
+	if outcome == outcomeFailed {
+		a.span.RecordError(a.err)
+		a.span.SetStatus(codes.Error, a.err.Error())
+	}
+	a.span.End()
+	return a.value, a.err
+}
@@ -0,0 +1,182 @@
+package hedge
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// call comportamento de uma tentativa: responde value ou err depois de delay
+type call struct {
+	delay time.Duration
+	value string
+	err   error
+}
+
+func TestDo(t *testing.T) {
+	errPrimary := errors.New("primary failed")
+	errHedge := errors.New("hedge failed")
+
+	tests := []struct {
+		name      string
+		primary   call
+		hedge     call
+		want      string
+		wantErr   error
+		wantHedge bool
+		// wantCancelled a tentativa perdedora recebe o cancelamento
+		wantCancelled bool
+	}{
+		{
+			name:    "primary before the delay",
+			primary: call{value: "primary"},
+			want:    "primary",
+		},
+		{
+			name:    "primary error before the delay",
+			primary: call{err: errPrimary},
+			wantErr: errPrimary,
+		},
+		{
+			name:          "hedge wins",
+			primary:       call{delay: time.Second, value: "primary"},
+			hedge:         call{value: "hedge"},
+			want:          "hedge",
+			wantHedge:     true,
+			wantCancelled: true,
+		},
+		{
+			name:      "primary wins after the hedge fails",
+			primary:   call{delay: 50 * time.Millisecond, value: "primary"},
+			hedge:     call{err: errHedge},
+			want:      "primary",
+			wantHedge: true,
+		},
+		{
+			name:      "both fail",
+			primary:   call{delay: 50 * time.Millisecond, err: errPrimary},
+			hedge:     call{err: errHedge},
+			wantErr:   errPrimary,
+			wantHedge: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHedger("test", Policy{Percentile: 0.9, MinDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}, noop.NewTracerProvider().Tracer("test"))
+
+			var hedged, cancelled atomic.Bool
+			got, err := Do(context.Background(), h, func(ctx context.Context, hedge bool) (string, error) {
+				c := tt.primary
+				if hedge {
+					hedged.Store(true)
+					c = tt.hedge
+				}
+				select {
+				case <-time.After(c.delay):
+					return c.value, c.err
+				case <-ctx.Done():
+					cancelled.Store(true)
+					return "", ctx.Err()
+				}
+			})
+
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() = (%q, %v), want (%q, %v)", got, err, tt.want, tt.wantErr)
+			}
+			if hedged.Load() != tt.wantHedge {
+				t.Errorf("hedge fired = %v, want %v", hedged.Load(), tt.wantHedge)
+			}
+			if tt.wantCancelled {
+				deadline := time.Now().Add(time.Second)
+				for !cancelled.Load() && time.Now().Before(deadline) {
+					time.Sleep(time.Millisecond)
+				}
+				if !cancelled.Load() {
+					t.Error("losing attempt was not cancelled")
+				}
+			}
+		})
+	}
+}
+
+func TestDoObservesFromPrimaryStart(t *testing.T) {
+	delay := 10 * time.Millisecond
+	h := NewHedger("test", Policy{Percentile: 0.9, MinDelay: time.Millisecond, MaxDelay: delay}, noop.NewTracerProvider().Tracer("test"))
+
+	// A segunda tentativa responde na hora, mas a chamada levou pelo menos o atraso
+	_, err := Do(context.Background(), h, func(ctx context.Context, hedge bool) (string, error) {
+		if hedge {
+			return "hedge", nil
+		}
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.samples) != 1 || h.samples[0] < delay {
+		t.Errorf("samples = %v, want a single sample of at least %v", h.samples, delay)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	policy := Policy{Percentile: 0.5, MinDelay: 5 * time.Millisecond, MaxDelay: 15 * time.Millisecond}
+
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		want    time.Duration
+	}{
+		{
+			name: "without enough samples",
+			want: policy.MaxDelay,
+		},
+		{
+			name:    "percentile",
+			samples: durations(1, 20),
+			want:    10 * time.Millisecond,
+		},
+		{
+			name:    "below MinDelay",
+			samples: durations(1, 4),
+			want:    policy.MinDelay,
+		},
+		{
+			name:    "above MaxDelay",
+			samples: durations(20, 40),
+			want:    policy.MaxDelay,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHedger("test", policy, noop.NewTracerProvider().Tracer("test"))
+			for _, sample := range tt.samples {
+				h.observe(sample)
+			}
+			if got := h.Delay(); got != tt.want {
+				t.Errorf("Delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// durations amostras de from a to milissegundos, completadas até minSamples com to
+func durations(from, to int) []time.Duration {
+	var samples []time.Duration
+	for ms := from; ms <= to; ms++ {
+		samples = append(samples, time.Duration(ms)*time.Millisecond)
+	}
+	for len(samples) < minSamples {
+		samples = append(samples, time.Duration(to)*time.Millisecond)
+	}
+	return samples
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// level nível mínimo dos logs, alterável em execução (recarga de configuração)
+var level = new(slog.LevelVar)
+
+// Setup direciona o slog e o pacote log para stderr a partir do nível informado.
+// Chamadas a log.Printf são registradas no nível INFO.
+func Setup(name string) error {
+	if err := SetLevel(name); err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+	return nil
+}
+
+// SetLevel altera o nível mínimo dos logs
+func SetLevel(name string) error {
+	l, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// ParseLevel converte debug, info, warn ou error no nível do slog
+func ParseLevel(name string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", name)
+	}
+	return l, nil
+}
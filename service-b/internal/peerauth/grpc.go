@@ -0,0 +1,71 @@
+package peerauth
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// grpcMethod método usado na assinatura dos tokens das chamadas gRPC (o caminho é o
+// nome completo do método, ex.: /weather.v1.WeatherService/GetWeatherByCEP)
+const grpcMethod = "GRPC"
+
+// healthService serviço de health padrão do gRPC, acessível sem credenciais como o /healthz
+const healthService = "/grpc.health.v1.Health/"
+
+// tokenMetadata chave de metadata com o token assinado do modo hmac
+var tokenMetadata = strings.ToLower(TokenHeader)
+
+// UnaryServerInterceptor equivalente gRPC do Middleware: recusa com Unauthenticated
+func (v *Verifier) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := v.authenticateRPC(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor equivalente gRPC do Middleware para chamadas com streaming
+func (v *Verifier) StreamServerInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if _, err := v.authenticateRPC(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (v *Verifier) authenticateRPC(ctx context.Context, fullMethod string) (context.Context, error) {
+	if v.cfg.Mode == ModeNone || strings.HasPrefix(fullMethod, healthService) {
+		return ctx, nil
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(tokenMetadata); len(values) > 0 {
+			token = values[0]
+		}
+	}
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+	}
+
+	peerID, reason := v.authenticate(state, token, grpcMethod, fullMethod)
+	if reason != "" {
+		v.reject(ctx, reason)
+		return ctx, status.Error(codes.Unauthenticated, "service authentication failed")
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(PeerAttribute, peerID))
+	return ctx, nil
+}
@@ -0,0 +1,94 @@
+package peerauth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Verifier exige credenciais de serviço válidas nas requisições recebidas
+type Verifier struct {
+	cfg Config
+}
+
+// NewVerifier cria um Verifier para o modo configurado
+func NewVerifier(cfg Config) *Verifier {
+	return &Verifier{cfg: cfg}
+}
+
+// Middleware recusa com 401 chamadas sem certificado de cliente (mtls) ou sem token
+// válido (hmac). A rejeição marca o span de servidor com status Error e
+// service_auth.rejected e incrementa service_auth_rejections_total.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	if v.cfg.Mode == ModeNone {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peer, reason := v.authenticate(r.TLS, r.Header.Get(TokenHeader), r.Method, r.URL.Path)
+		if reason != "" {
+			v.reject(r.Context(), reason)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "service authentication failed"})
+			return
+		}
+
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String(PeerAttribute, peer))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reject registra a rejeição na métrica e no span de servidor
+func (v *Verifier) reject(ctx context.Context, reason string) {
+	rejections.WithLabelValues(v.cfg.Mode, reason).Inc()
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Bool(RejectedAttribute, true),
+		attribute.String(ReasonAttribute, reason),
+	)
+	span.SetStatus(codes.Error, "service authentication rejected: "+reason)
+}
+
+// authenticate retorna a identidade do serviço chamador ou o motivo da rejeição, a
+// partir do estado TLS da conexão (mtls) ou do token da chamada (hmac)
+func (v *Verifier) authenticate(state *tls.ConnectionState, token, method, path string) (string, string) {
+	var peer string
+	switch v.cfg.Mode {
+	case ModeMTLS:
+		// O handshake já validou a cadeia contra a CA
+		if state == nil || len(state.PeerCertificates) == 0 {
+			return "", ReasonMissingCredentials
+		}
+		cert := state.PeerCertificates[0]
+		for _, name := range append([]string{cert.Subject.CommonName}, cert.DNSNames...) {
+			if v.allowed(name) {
+				return name, ""
+			}
+		}
+		return "", ReasonUnknownPeer
+	case ModeHMAC:
+		if token == "" {
+			return "", ReasonMissingCredentials
+		}
+		var reason string
+		peer, reason = verifyToken(token, v.cfg.Secret, method, path, time.Now(), v.cfg.TokenTTL)
+		if reason != "" {
+			return "", reason
+		}
+		if !v.allowed(peer) {
+			return "", ReasonUnknownPeer
+		}
+	}
+	return peer, ""
+}
+
+func (v *Verifier) allowed(peer string) bool {
+	return peer != "" && (len(v.cfg.AllowedPeers) == 0 || slices.Contains(v.cfg.AllowedPeers, peer))
+}
@@ -0,0 +1,20 @@
+package peerauth
+
+import (
+	"crypto/tls"
+
+	"github.com/marfebr/otel-lab/service-b/internal/tlsconfig"
+)
+
+// VerifyClients configura o listener para verificar certificados de cliente contra a
+// CA no handshake. O certificado é exigido apenas pelo Middleware, para que as
+// rejeições gerem span e métrica e os endpoints de saúde continuem acessíveis.
+func VerifyClients(tlsConfig *tls.Config, cfg Config) error {
+	pool, err := tlsconfig.LoadCAPool(cfg.CAFile)
+	if err != nil {
+		return err
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	return nil
+}
@@ -0,0 +1,46 @@
+package peerauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validPeerID IDs seguros para o token e para atributos de span
+var validPeerID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// verifyToken valida o token "<id>.<expiração unix>.<assinatura>", assinado pelo
+// service-a para o método e o caminho da chamada, e retorna o ID do emissor ou o
+// motivo da rejeição; tokens com validade maior que maxTTL são recusados
+func verifyToken(token, secret, method, path string, now time.Time, maxTTL time.Duration) (string, string) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || !validPeerID.MatchString(parts[0]) {
+		return "", ReasonMalformedToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ReasonMalformedToken
+	}
+
+	expected := signature(secret, parts[0]+"."+parts[1], method, path)
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return "", ReasonBadSignature
+	}
+
+	remaining := time.Unix(expires, 0).Sub(now)
+	if remaining <= 0 || remaining > maxTTL+time.Second {
+		return "", ReasonExpiredToken
+	}
+
+	return parts[0], ""
+}
+
+func signature(secret, payload, method, path string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload + "\n" + method + "\n" + path))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
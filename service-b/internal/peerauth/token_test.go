@@ -0,0 +1,125 @@
+package peerauth
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyToken(t *testing.T) {
+	const (
+		secret = "s3cret"
+		method = "POST"
+		path   = "/weather"
+		maxTTL = 30 * time.Second
+	)
+	now := time.Unix(1_700_000_000, 0)
+	valid := signToken(secret, "service-a", method, path, now.Add(10*time.Second))
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		wantID string
+		reason string
+	}{
+		{
+			name:   "valid",
+			token:  valid,
+			wantID: "service-a",
+		},
+		{
+			name:   "valid at the TTL cap",
+			token:  signToken(secret, "service-a", method, path, now.Add(maxTTL)),
+			wantID: "service-a",
+		},
+		{
+			name:   "expired",
+			token:  signToken(secret, "service-a", method, path, now.Add(-time.Second)),
+			reason: ReasonExpiredToken,
+		},
+		{
+			name:   "expires now",
+			token:  signToken(secret, "service-a", method, path, now),
+			reason: ReasonExpiredToken,
+		},
+		{
+			name:   "validity above the TTL cap",
+			token:  signToken(secret, "service-a", method, path, now.Add(maxTTL+2*time.Second)),
+			reason: ReasonExpiredToken,
+		},
+		{
+			name:   "other method",
+			token:  valid,
+			method: "GET",
+			reason: ReasonBadSignature,
+		},
+		{
+			name:   "other path",
+			token:  valid,
+			path:   "/weather/city",
+			reason: ReasonBadSignature,
+		},
+		{
+			name:   "other secret",
+			token:  signToken("other", "service-a", method, path, now.Add(10*time.Second)),
+			reason: ReasonBadSignature,
+		},
+		{
+			name:   "extended expiry",
+			token:  withPart(valid, 1, strconv.FormatInt(now.Add(20*time.Second).Unix(), 10)),
+			reason: ReasonBadSignature,
+		},
+		{
+			name:   "other issuer",
+			token:  withPart(valid, 0, "service-c"),
+			reason: ReasonBadSignature,
+		},
+		{
+			name:   "missing signature",
+			token:  valid[:strings.LastIndex(valid, ".")],
+			reason: ReasonMalformedToken,
+		},
+		{
+			name:   "invalid issuer",
+			token:  withPart(valid, 0, "service a"),
+			reason: ReasonMalformedToken,
+		},
+		{
+			name:   "invalid expiry",
+			token:  withPart(valid, 1, "soon"),
+			reason: ReasonMalformedToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.method == "" {
+				tt.method = method
+			}
+			if tt.path == "" {
+				tt.path = path
+			}
+
+			id, reason := verifyToken(tt.token, secret, tt.method, tt.path, now, maxTTL)
+			if id != tt.wantID || reason != tt.reason {
+				t.Errorf("verifyToken() = (%q, %q), want (%q, %q)", id, reason, tt.wantID, tt.reason)
+			}
+		})
+	}
+}
+
+// signToken assina o token como o service-a
+func signToken(secret, id, method, path string, expires time.Time) string {
+	payload := id + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + signature(secret, payload, method, path)
+}
+
+// withPart troca uma das partes "<id>.<expiração>.<assinatura>" do token
+func withPart(token string, i int, value string) string {
+	parts := strings.Split(token, ".")
+	parts[i] = value
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,245 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Janelas de cota
+const (
+	WindowSecond = "second"
+	WindowDay    = "day"
+)
+
+// ErrQuotaExhausted erro retornado quando a cota de um provedor acabou
+var ErrQuotaExhausted = errors.New("upstream quota exhausted")
+
+// ExhaustedError detalha a cota esgotada; errors.Is(err, ErrQuotaExhausted) é verdadeiro
+type ExhaustedError struct {
+	Provider string
+	Window   string
+	// RetryAfter tempo estimado até haver cota novamente
+	RetryAfter time.Duration
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("upstream quota exhausted: %s per-%s limit reached", e.Provider, e.Window)
+}
+
+// Is permite comparar com ErrQuotaExhausted
+func (e *ExhaustedError) Is(target error) bool {
+	return target == ErrQuotaExhausted
+}
+
+// Limits cotas de um provedor; zero desativa a janela
+type Limits struct {
+	PerSecond float64
+	PerDay    int
+	// MaxWait quanto uma chamada pode esperar na fila por cota da janela de segundo
+	MaxWait time.Duration
+}
+
+// burst capacidade do balde da janela de segundo; ao menos uma ficha, para que
+// taxas abaixo de 1 por segundo admitam uma chamada a cada 1/PerSecond segundos
+func (l Limits) burst() float64 {
+	return math.Max(1, l.PerSecond)
+}
+
+// quotaRejections contador de chamadas rejeitadas por falta de cota
+var quotaRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "upstream_quota_rejections_total",
+	Help: "Outbound calls rejected because the provider quota was exhausted.",
+}, []string{"provider", "window"})
+
+// remainingDesc gauge de cota restante, calculado no momento da coleta
+var remainingDesc = prometheus.NewDesc(
+	"upstream_quota_remaining",
+	"Remaining outbound call budget by provider and window (second, day).",
+	[]string{"provider", "window"}, nil,
+)
+
+// governors coletor com todos os Governors criados
+var governors = &collector{}
+
+func init() {
+	prometheus.MustRegister(governors)
+}
+
+type collector struct {
+	mu   sync.Mutex
+	list []*Governor
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- remainingDesc
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, g := range c.list {
+		second, day := g.remaining(time.Now())
+		if second >= 0 {
+			ch <- prometheus.MustNewConstMetric(remainingDesc, prometheus.GaugeValue, second, g.provider, WindowSecond)
+		}
+		if day >= 0 {
+			ch <- prometheus.MustNewConstMetric(remainingDesc, prometheus.GaugeValue, day, g.provider, WindowDay)
+		}
+	}
+}
+
+// Governor limita as chamadas de saída a um provedor por segundo (token bucket,
+// com fila curta) e por dia (UTC). O estado é local à réplica.
+type Governor struct {
+	provider string
+
+	mu     sync.Mutex
+	limits Limits
+	tokens float64
+	last   time.Time
+	day    string
+	used   int
+}
+
+// NewGovernor cria o Governor do provedor
+func NewGovernor(provider string, limits Limits) *Governor {
+	g := &Governor{
+		provider: provider,
+		limits:   limits,
+		tokens:   limits.burst(),
+		last:     time.Now(),
+		day:      time.Now().UTC().Format(time.DateOnly),
+	}
+
+	governors.mu.Lock()
+	governors.list = append(governors.list, g)
+	governors.mu.Unlock()
+
+	return g
+}
+
+// SetLimits troca as cotas (recarga de configuração), preservando o consumo do dia
+func (g *Governor) SetLimits(limits Limits) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.limits = limits
+	g.tokens = math.Min(g.tokens, limits.burst())
+}
+
+// Acquire reserva uma chamada. Sem cota no segundo, espera até MaxWait; sem cota
+// no dia, falha imediatamente. Um Governor nil não limita.
+func (g *Governor) Acquire(ctx context.Context) error {
+	if g == nil {
+		return nil
+	}
+
+	wait, err := g.reserve(time.Now())
+	if err != nil {
+		return err
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		g.refund()
+		return ctx.Err()
+	}
+}
+
+// reserve consome a cota e retorna quanto esperar pela ficha do segundo
+func (g *Governor) reserve(now time.Time) (time.Duration, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if day := now.UTC().Format(time.DateOnly); day != g.day {
+		g.day = day
+		g.used = 0
+	}
+
+	if g.limits.PerDay > 0 && g.used >= g.limits.PerDay {
+		quotaRejections.WithLabelValues(g.provider, WindowDay).Inc()
+		return 0, &ExhaustedError{Provider: g.provider, Window: WindowDay, RetryAfter: untilMidnight(now)}
+	}
+
+	var wait time.Duration
+	if g.limits.PerSecond > 0 {
+		g.tokens = math.Min(g.limits.burst(), g.tokens+now.Sub(g.last).Seconds()*g.limits.PerSecond)
+		g.last = now
+
+		// Fichas negativas representam chamadas já na fila
+		wait = time.Duration((1 - g.tokens) / g.limits.PerSecond * float64(time.Second))
+		if wait > g.limits.MaxWait {
+			quotaRejections.WithLabelValues(g.provider, WindowSecond).Inc()
+			return 0, &ExhaustedError{Provider: g.provider, Window: WindowSecond, RetryAfter: wait}
+		}
+		g.tokens--
+	}
+
+	g.used++
+
+	return wait, nil
+}
+
+// Refund devolve a cota reservada por Acquire para uma chamada que não chegou a ser
+// feita (ex.: rejeitada pelo bulkhead). Um Governor nil não limita.
+func (g *Governor) Refund() {
+	if g == nil {
+		return
+	}
+	g.refund()
+}
+
+// refund devolve a cota de uma chamada que desistiu da fila
+func (g *Governor) refund() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.limits.PerSecond > 0 {
+		g.tokens = math.Min(g.limits.burst(), g.tokens+1)
+	}
+	if g.used > 0 {
+		g.used--
+	}
+}
+
+// remaining calcula a cota restante em cada janela sem consumi-la (-1 se desativada)
+func (g *Governor) remaining(now time.Time) (second, day float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	second, day = -1, -1
+	if g.limits.PerSecond > 0 {
+		tokens := math.Min(g.limits.burst(), g.tokens+now.Sub(g.last).Seconds()*g.limits.PerSecond)
+		second = math.Max(0, math.Floor(tokens))
+	}
+	if g.limits.PerDay > 0 {
+		used := g.used
+		if now.UTC().Format(time.DateOnly) != g.day {
+			used = 0
+		}
+		day = float64(max(0, g.limits.PerDay-used))
+	}
+	return second, day
+}
+
+// untilMidnight tempo até a virada do dia em UTC
+func untilMidnight(now time.Time) time.Duration {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return midnight.Sub(now)
+}
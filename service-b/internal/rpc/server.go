@@ -0,0 +1,142 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-b/internal/fault"
+	"github.com/marfebr/otel-lab/service-b/internal/peerauth"
+	"github.com/marfebr/otel-lab/service-b/internal/shed"
+	"github.com/marfebr/otel-lab/service-b/internal/weatherpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer cria o servidor gRPC com o WeatherService e o serviço de health, que
+// responde pelo WeatherService conforme ready (a prontidão do /readyz).
+// As chamadas são instrumentadas pelo otelgrpc (spans de servidor e propagação de
+// contexto, exceto health), passam pelo controle de admissão compartilhado com o
+// HTTP e são autenticadas pelo verifier; o prazo (grpc-timeout) é limitado a
+// requestTimeout e faults injeta as falhas configuradas por método.
+// tlsConfig nil serve sem TLS.
+func NewServer(weather *WeatherServer, ready func(context.Context) bool, verifier *peerauth.Verifier, admission *shed.Limiter, faults *fault.Injector, tlsConfig *tls.Config, requestTimeout time.Duration) *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
+		grpc.ChainUnaryInterceptor(admissionUnaryInterceptor(admission), verifier.UnaryServerInterceptor, deadlineUnaryInterceptor(requestTimeout), faults.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(admissionStreamInterceptor(admission), verifier.StreamServerInterceptor, deadlineStreamInterceptor(requestTimeout), faults.StreamServerInterceptor),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	server := grpc.NewServer(opts...)
+	weatherpb.RegisterWeatherServiceServer(server, weather)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(weatherpb.WeatherService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, readinessHealth{Server: healthServer, ready: ready})
+
+	return server
+}
+
+// readinessHealth responde o Check do WeatherService com a prontidão do serviço, para
+// que o cliente gRPC veja o mesmo estado do /readyz; o restante segue o health padrão
+type readinessHealth struct {
+	*health.Server
+	ready func(context.Context) bool
+}
+
+func (h readinessHealth) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.GetService() == weatherpb.WeatherService_ServiceDesc.ServiceName && !h.ready(ctx) {
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+	}
+	return h.Server.Check(ctx, req)
+}
+
+// deadlineUnaryInterceptor aplica requestTimeout às chamadas unárias; o deadline
+// enviado pelo cliente prevalece quando é menor
+func deadlineUnaryInterceptor(requestTimeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// deadlineStreamInterceptor aplica requestTimeout às chamadas com stream, do início
+// ao fim do stream
+func deadlineStreamInterceptor(requestTimeout time.Duration) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := context.WithTimeout(ss.Context(), requestTimeout)
+		defer cancel()
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream stream com o contexto do prazo aplicado
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// admissionUnaryInterceptor aplica o controle de admissão às chamadas unárias; o
+// health check sempre é admitido e as descartadas recebem Unavailable com retry-after
+func admissionUnaryInterceptor(admission *shed.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, err := admission.Admit(ctx, methodPriority(info.FullMethod))
+		if err != nil {
+			if errors.Is(err, shed.ErrOverloaded) {
+				grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterMetadata, "1"))
+			}
+			return nil, shedStatus(err)
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// admissionStreamInterceptor aplica o controle de admissão às chamadas com stream,
+// que ocupam a vaga até o fim do stream
+func admissionStreamInterceptor(admission *shed.Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := admission.Admit(ss.Context(), methodPriority(info.FullMethod))
+		if err != nil {
+			if errors.Is(err, shed.ErrOverloaded) {
+				ss.SetTrailer(metadata.Pairs(RetryAfterMetadata, "1"))
+			}
+			return shedStatus(err)
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
+
+// methodPriority classe de admissão do método: o health check sempre entra
+func methodPriority(fullMethod string) shed.Priority {
+	if strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+		return shed.PriorityCritical
+	}
+	return shed.PriorityNormal
+}
+
+// shedStatus converte o descarte na mesma mensagem da resposta 503 do HTTP
+func shedStatus(err error) error {
+	if errors.Is(err, shed.ErrOverloaded) {
+		return status.Error(codes.Unavailable, shed.ErrOverloaded.Error())
+	}
+	return status.FromContextError(err).Err()
+}
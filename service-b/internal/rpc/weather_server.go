@@ -0,0 +1,134 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/marfebr/otel-lab/service-b/internal/bulkhead"
+	"github.com/marfebr/otel-lab/service-b/internal/quota"
+	"github.com/marfebr/otel-lab/service-b/internal/service"
+	"github.com/marfebr/otel-lab/service-b/internal/weatherpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchSize quantidade máxima de CEPs por chamada de GetWeatherBatch
+const maxBatchSize = 100
+
+// RetryAfterMetadata trailer com os segundos de espera quando a cota do provedor acaba,
+// o limite de concorrência é atingido ou a requisição é descartada por sobrecarga
+const RetryAfterMetadata = "retry-after"
+
+// WeatherServer implementa o WeatherService sobre o mesmo orquestrador do POST /weather
+type WeatherServer struct {
+	weatherpb.UnimplementedWeatherServiceServer
+	orchestrator *service.WeatherOrchestrator
+}
+
+// NewWeatherServer cria uma nova instância do WeatherServer
+func NewWeatherServer(orchestrator *service.WeatherOrchestrator) *WeatherServer {
+	return &WeatherServer{orchestrator: orchestrator}
+}
+
+// GetWeatherByCEP busca o clima atual pelo CEP
+func (s *WeatherServer) GetWeatherByCEP(ctx context.Context, req *weatherpb.GetWeatherByCEPRequest) (*weatherpb.Weather, error) {
+	resp, err := s.orchestrator.GetWeatherByCEP(ctx, req.GetCep())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toWeather(resp), nil
+}
+
+// GetWeatherByCity busca o clima atual pela cidade
+func (s *WeatherServer) GetWeatherByCity(ctx context.Context, req *weatherpb.GetWeatherByCityRequest) (*weatherpb.Weather, error) {
+	if req.GetCity() == "" {
+		return nil, status.Error(codes.InvalidArgument, "city is required")
+	}
+	resp, err := s.orchestrator.GetWeatherByCity(ctx, req.GetCity())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toWeather(resp), nil
+}
+
+// GetWeatherBatch consulta os CEPs em sequência (respeitando as cotas dos provedores)
+// e envia cada resultado assim que fica pronto; falhas de um CEP não encerram o lote
+func (s *WeatherServer) GetWeatherBatch(req *weatherpb.GetWeatherBatchRequest, stream grpc.ServerStreamingServer[weatherpb.WeatherBatchResult]) error {
+	ceps := req.GetCeps()
+	if len(ceps) == 0 || len(ceps) > maxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "ceps must have between 1 and %d items", maxBatchSize)
+	}
+
+	ctx := stream.Context()
+	for _, cep := range ceps {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		result := &weatherpb.WeatherBatchResult{Cep: cep}
+		resp, err := s.orchestrator.GetWeatherByCEP(ctx, cep)
+		if err != nil {
+			st := toStatus(err)
+			result.Result = &weatherpb.WeatherBatchResult_Error{Error: &weatherpb.BatchError{
+				Code:    int32(st.Code()),
+				Message: st.Message(),
+			}}
+		} else {
+			result.Result = &weatherpb.WeatherBatchResult_Weather{Weather: toWeather(resp)}
+		}
+
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusError converte o erro do orquestrador e, se a cota acabou ou o limite de
+// concorrência foi atingido, informa a espera no trailer retry-after (como o
+// Retry-After do POST /weather)
+func statusError(ctx context.Context, err error) error {
+	var exhausted *quota.ExhaustedError
+	switch {
+	case errors.As(err, &exhausted):
+		seconds := strconv.Itoa(int(math.Ceil(exhausted.RetryAfter.Seconds())))
+		grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterMetadata, seconds))
+	case errors.Is(err, bulkhead.ErrRejected):
+		grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterMetadata, "1"))
+	}
+	return toStatus(err).Err()
+}
+
+// toStatus usa as mesmas mensagens das respostas de erro do POST /weather
+func toStatus(err error) *status.Status {
+	switch {
+	case errors.Is(err, service.ErrInvalidCEP):
+		return status.New(codes.InvalidArgument, service.ErrInvalidCEP.Error())
+	case errors.Is(err, service.ErrCEPNotFound):
+		return status.New(codes.NotFound, service.ErrCEPNotFound.Error())
+	case errors.Is(err, service.ErrCityNotFound):
+		return status.New(codes.NotFound, service.ErrCityNotFound.Error())
+	case errors.Is(err, quota.ErrQuotaExhausted):
+		return status.New(codes.ResourceExhausted, quota.ErrQuotaExhausted.Error())
+	case errors.Is(err, bulkhead.ErrRejected):
+		return status.New(codes.ResourceExhausted, bulkhead.ErrRejected.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.FromContextError(err)
+	default:
+		return status.New(codes.Internal, "internal server error")
+	}
+}
+
+func toWeather(resp *service.WeatherResponse) *weatherpb.Weather {
+	return &weatherpb.Weather{
+		City:   resp.City,
+		TempC:  resp.TempC,
+		TempF:  resp.TempF,
+		TempK:  resp.TempK,
+		Source: resp.Source,
+	}
+}
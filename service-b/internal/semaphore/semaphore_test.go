@@ -0,0 +1,147 @@
+package semaphore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcquireCancel(t *testing.T) {
+	tests := []struct {
+		name string
+		// granted concede a vaga à chamada cancelada antes que ela desista da fila
+		granted bool
+	}{
+		{name: "cancelled while queued"},
+		{name: "cancelled after grant", granted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(1, 2, nil)
+			if _, err := s.Acquire(context.Background(), time.Minute); err != nil {
+				t.Fatalf("Acquire() error = %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancelled := make(chan error, 1)
+			go func() {
+				_, err := s.Acquire(ctx, time.Minute)
+				cancelled <- err
+			}()
+			waitQueued(t, s, 1)
+
+			next := make(chan struct{})
+			go func() {
+				if _, err := s.Acquire(context.Background(), time.Minute); err != nil {
+					t.Errorf("Acquire() of the next call error = %v", err)
+				}
+				close(next)
+			}()
+			waitQueued(t, s, 2)
+
+			if tt.granted {
+				// Com mu travado, a chamada cancelada só trata o cancelamento depois
+				// que a primeira vaga é liberada e concedida a ela
+				s.mu.Lock()
+				cancel()
+				time.Sleep(20 * time.Millisecond)
+				s.inFlight--
+				s.grant()
+				s.mu.Unlock()
+			} else {
+				cancel()
+			}
+
+			if err := <-cancelled; !errors.Is(err, context.Canceled) {
+				t.Fatalf("Acquire() of the cancelled call error = %v, want %v", err, context.Canceled)
+			}
+			if !tt.granted {
+				s.Release()
+			}
+
+			select {
+			case <-next:
+				s.Release()
+			case <-time.After(time.Second):
+				t.Fatal("next queued call was not granted the slot")
+			}
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.inFlight != 0 || len(s.queue) != 0 {
+				t.Errorf("after release: inFlight = %d, queued = %d, want 0 and 0", s.inFlight, len(s.queue))
+			}
+		})
+	}
+}
+
+func TestAcquireRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxQueue int
+		timeout  time.Duration
+		wantErr  error
+	}{
+		{
+			name:    "queue full",
+			timeout: time.Minute,
+			wantErr: ErrQueueFull,
+		},
+		{
+			name:     "queue timeout",
+			maxQueue: 1,
+			timeout:  10 * time.Millisecond,
+			wantErr:  ErrQueueTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(1, tt.maxQueue, nil)
+			if _, err := s.Acquire(context.Background(), tt.timeout); err != nil {
+				t.Fatalf("Acquire() error = %v", err)
+			}
+
+			if _, err := s.Acquire(context.Background(), tt.timeout); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Acquire() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(s.queue) != 0 {
+				t.Errorf("queued = %d after rejection, want 0", len(s.queue))
+			}
+		})
+	}
+}
+
+func TestTryAcquire(t *testing.T) {
+	s := New(2, 1, nil)
+	if !s.TryAcquire(1) {
+		t.Fatal("TryAcquire(1) with no slot in use = false, want true")
+	}
+	if s.TryAcquire(1) {
+		t.Error("TryAcquire(1) with one slot in use = true, want false")
+	}
+	if !s.TryAcquire(3) {
+		t.Fatal("TryAcquire(3) with one of two slots in use = false, want true")
+	}
+	if s.TryAcquire(3) {
+		t.Error("TryAcquire(3) with every slot in use = true, want false")
+	}
+}
+
+// waitQueued espera até n chamadas estarem na fila
+func waitQueued(t *testing.T, s *Semaphore, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		queued := len(s.queue)
+		s.mu.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("queued calls did not reach %d", n)
+}
@@ -4,6 +4,7 @@ import "errors"
 
 // Erros específicos do serviço
 var (
-	ErrInvalidCEP  = errors.New("invalid zipcode")
-	ErrCEPNotFound = errors.New("can not find zipcode")
+	ErrInvalidCEP   = errors.New("invalid zipcode")
+	ErrCEPNotFound  = errors.New("can not find zipcode")
+	ErrCityNotFound = errors.New("can not find city")
 )
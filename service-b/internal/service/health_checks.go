@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-b/internal/quota"
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
+)
+
+// CheckReachable verifica se o upstream de baseURL aceita conexões: abre uma conexão
+// TCP e, para https, completa o handshake TLS com tlsConfig (nil usa o padrão). Não
+// envia requisições, então não consome a cota do provedor nem depende da chave de API.
+func CheckReachable(ctx context.Context, baseURL string, tlsConfig *tls.Config) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL, fmt.Errorf("invalid upstream URL: %w", err)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	addr := net.JoinHostPort(u.Hostname(), port)
+
+	var conn net.Conn
+	if u.Scheme == "https" {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return baseURL, err
+	}
+	conn.Close()
+
+	return baseURL, nil
+}
+
+// WeatherAPICredentials verifica a chave da WeatherAPI com uma chamada autenticada
+// leve (search.json). A chamada passa pela cota do provedor e o resultado definitivo
+// (chave aceita ou rejeitada) é reaproveitado por ttl, para que a prontidão consuma
+// no máximo uma chamada por ttl.
+type WeatherAPICredentials struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	quota   *quota.Governor
+
+	mu      sync.Mutex
+	ttl     time.Duration
+	checked bool
+	err     error
+	expires time.Time
+}
+
+// NewWeatherAPICredentials cria a verificação; client não deve ser instrumentado para
+// não gerar um trace por probe
+func NewWeatherAPICredentials(client *http.Client, baseURL, apiKey string, governor *quota.Governor, ttl time.Duration) *WeatherAPICredentials {
+	return &WeatherAPICredentials{
+		client:  client,
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		quota:   governor,
+		ttl:     ttl,
+	}
+}
+
+// SetTTL altera por quanto tempo o resultado é reaproveitado
+func (c *WeatherAPICredentials) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expires = c.expires.Add(ttl - c.ttl)
+	c.ttl = ttl
+}
+
+// Check retorna o último resultado ainda válido ou consulta a WeatherAPI. Sem cota
+// disponível, mantém o último resultado conhecido; falhas de rede ou status
+// inesperados não ficam em cache.
+func (c *WeatherAPICredentials) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.checked && time.Now().Before(c.expires) {
+		return c.err
+	}
+	if err := c.quota.Acquire(ctx); err != nil {
+		if errors.Is(err, quota.ErrQuotaExhausted) {
+			return c.err
+		}
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/search.json?key="+url.QueryEscape(c.apiKey)+"&q=London", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return telemetry.RedactError(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		c.err = nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		c.err = errors.New("weatherapi rejected the API key")
+	default:
+		return fmt.Errorf("weatherapi status: %d", resp.StatusCode)
+	}
+	c.checked = true
+	c.expires = time.Now().Add(c.ttl)
+	return c.err
+}
@@ -11,6 +11,8 @@ type WeatherResponse struct {
 	TempC float64 `json:"temp_C"`
 	TempF float64 `json:"temp_F"`
 	TempK float64 `json:"temp_K"`
+	// Source origem dos dados (live, mock ou fixture), enviada no header X-Weather-Source
+	Source string `json:"-"`
 }
 
 // ErrorResponse representa uma resposta de erro
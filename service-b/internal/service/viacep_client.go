@@ -2,14 +2,14 @@ package service
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -25,7 +25,8 @@ func NewViaCEPClient(baseURL string, tracer trace.Tracer) *ViaCEPClient {
 	return &ViaCEPClient{
 		baseURL: baseURL,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: telemetry.NewTransport(nil),
 		},
 		tracer: tracer,
 	}
@@ -42,14 +43,14 @@ func (c *ViaCEPClient) GetAddressByCEP(ctx context.Context, cep string) (*ViaCEP
 	// Criar requisição HTTP
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Executar requisição
 	resp, err := c.client.Do(req)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
@@ -57,26 +58,26 @@ func (c *ViaCEPClient) GetAddressByCEP(ctx context.Context, cep string) (*ViaCEP
 	// Ler resposta
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// Decodificar resposta
 	var viaCEPResp ViaCEPResponse
 	if err := json.Unmarshal(body, &viaCEPResp); err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	// Verificar se o CEP foi encontrado
 	if viaCEPResp.Erro {
-		span.RecordError(ErrCEPNotFound)
+		telemetry.RecordError(span, ErrCEPNotFound)
 		return nil, ErrCEPNotFound
 	}
 
 	// Verificar se a localidade está vazia (CEP inválido)
 	if viaCEPResp.Localidade == "" {
-		span.RecordError(ErrCEPNotFound)
+		telemetry.RecordError(span, ErrCEPNotFound)
 		return nil, ErrCEPNotFound
 	}
 
@@ -108,20 +109,27 @@ type AddressResponse struct {
 	Service      string `json:"service"`
 }
 
-func BuscaViaCepApi(cep string) (AddressResponse, error) {
-	return BuscaViaCepApiComURL(cep, "https://viacep.com.br/ws")
+// DefaultViaCEPBaseURL URL base da API ViaCEP
+const DefaultViaCEPBaseURL = "https://viacep.com.br/ws"
+
+// defaultClient cliente instrumentado usado quando nenhum é informado
+var defaultClient = &http.Client{Transport: telemetry.NewTransport(nil)}
+
+func BuscaViaCepApi(ctx context.Context, cep string) (AddressResponse, error) {
+	return BuscaViaCepApiComURL(ctx, nil, cep, DefaultViaCEPBaseURL)
 }
 
-func BuscaViaCepApiComURL(cep string, baseURL string) (AddressResponse, error) {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+// BuscaViaCepApiComURL busca o endereço do CEP no ViaCEP em baseURL; client deve ser
+// compartilhado entre as chamadas para reaproveitar conexões (nil usa o padrão)
+func BuscaViaCepApiComURL(ctx context.Context, client *http.Client, cep string, baseURL string) (AddressResponse, error) {
+	if client == nil {
+		client = defaultClient
 	}
-	client := http.Client{Transport: tr}
 
 	url := fmt.Sprintf("%s/%s/json/", baseURL, cep)
-	log.Printf("ViaCEP URL: %s", url)
+	slog.DebugContext(ctx, "ViaCEP request", "url", url)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return AddressResponse{}, err
 	}
@@ -138,7 +146,7 @@ func BuscaViaCepApiComURL(cep string, baseURL string) (AddressResponse, error) {
 	if err != nil {
 		return AddressResponse{}, err
 	}
-	log.Printf("ViaCEP response body: %s", string(body))
+	slog.DebugContext(ctx, "ViaCEP response", "body", string(body))
 	var viaCEP ViaCEPRequest
 	if err := json.Unmarshal(body, &viaCEP); err != nil {
 		return AddressResponse{}, err
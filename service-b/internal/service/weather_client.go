@@ -1,39 +1,38 @@
 package service
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 
-	"github.com/spf13/viper"
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
 )
 
-// GetWeatherAPICallWithURL busca clima por cidade usando WeatherAPI (padrão cloud-run)
-func GetWeatherAPICallWithURL(city string, baseURL string) (ResponseTemps, error) {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-
-	api := viper.GetString("WEATHER_API")
+// GetWeatherAPICallWithURL busca clima por cidade usando WeatherAPI (padrão cloud-run);
+// client deve ser compartilhado entre as chamadas (nil usa o padrão)
+func GetWeatherAPICallWithURL(ctx context.Context, client *http.Client, city string, baseURL string, api string) (ResponseTemps, error) {
 	if api == "" {
-		// MOCK: retorna dados fixos se não houver API key
-		return ResponseTemps{
-			TempC: 25.0,
-			TempF: 77.0,
-			TempK: 298.15,
-		}, nil
+		return ResponseTemps{}, errors.New("weatherapi key not configured")
+	}
+	if client == nil {
+		client = defaultClient
 	}
-	client := http.Client{Transport: tr}
 
 	encodedCity := url.QueryEscape(city)
 	url := baseURL + "/current.json?key=" + api + "&q=" + encodedCity + "&aqi=no"
 
-	resp, err := client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return ResponseTemps{}, err
 	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// A mensagem do *url.Error traz a URL com a chave da API
+		return ResponseTemps{}, telemetry.RedactError(err)
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -50,8 +49,3 @@ func GetWeatherAPICallWithURL(city string, baseURL string) (ResponseTemps, error
 		TempK: (weatherResponse.Current.TempC + 273.15),
 	}, nil
 }
-
-// GetWeatherAPICall mantém compatibilidade
-func GetWeatherAPICall(city string) (ResponseTemps, error) {
-	return GetWeatherAPICallWithURL(city, "https://api.weatherapi.com/v1")
-}
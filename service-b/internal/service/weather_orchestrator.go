@@ -2,22 +2,61 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"time"
 
-	"github.com/spf13/viper"
+	"github.com/marfebr/otel-lab/service-b/internal/bulkhead"
+	"github.com/marfebr/otel-lab/service-b/internal/deadline"
+	"github.com/marfebr/otel-lab/service-b/internal/hedge"
+	"github.com/marfebr/otel-lab/service-b/internal/quota"
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// weatherSourceKey atributo de span com a origem dos dados de clima
+const weatherSourceKey = attribute.Key("weather.source")
+
+// ProviderConfig endereço e cota do ViaCEP e limites de tempo dos provedores externos
+type ProviderConfig struct {
+	ViaCEPBaseURL string
+	// ViaCEPQuota cota de chamadas ao ViaCEP (nil não limita)
+	ViaCEPQuota *quota.Governor
+	// ViaCEPBulkhead chamadas simultâneas ao ViaCEP (nil não limita)
+	ViaCEPBulkhead *bulkhead.Bulkhead
+	// ViaCEPClient cliente HTTP compartilhado das chamadas ao ViaCEP (nil usa o padrão)
+	ViaCEPClient *http.Client
+	// Timeout limite de cada chamada, mesmo com mais tempo restante na requisição
+	Timeout time.Duration
+	// CEPBudgetShare fração do tempo restante da requisição dada à busca do CEP
+	CEPBudgetShare float64
+	// MinBudget tempo mínimo para chamar um provedor (abaixo disso falha com deadline.ErrExceeded)
+	MinBudget time.Duration
+	// Hedger dispara a segunda tentativa da busca de CEP (nil desativa)
+	Hedger *hedge.Hedger
+	// SecondaryBaseURL provedor compatível com o ViaCEP da segunda tentativa
+	// (vazio repete a chamada ao ViaCEP)
+	SecondaryBaseURL  string
+	SecondaryClient   *http.Client
+	SecondaryBulkhead *bulkhead.Bulkhead
+}
+
 // WeatherOrchestrator orquestra a busca de dados de clima por cidade
 type WeatherOrchestrator struct {
-	tracer trace.Tracer
+	tracer    trace.Tracer
+	weather   WeatherProvider
+	providers ProviderConfig
 }
 
 // NewWeatherOrchestrator cria uma nova instância do orquestrador
-func NewWeatherOrchestrator(tracer trace.Tracer) *WeatherOrchestrator {
+func NewWeatherOrchestrator(tracer trace.Tracer, weather WeatherProvider, providers ProviderConfig) *WeatherOrchestrator {
 	return &WeatherOrchestrator{
-		tracer: tracer,
+		tracer:    tracer,
+		weather:   weather,
+		providers: providers,
 	}
 }
 
@@ -26,19 +65,21 @@ func (o *WeatherOrchestrator) GetWeatherByCity(ctx context.Context, city string)
 	ctx, span := o.tracer.Start(ctx, "weather-orchestration")
 	defer span.End()
 
-	// Buscar dados de clima no WeatherAPI (padrão cloud-run)
-	temps, err := GetWeatherAPICall(city)
+	// Buscar dados de clima no provedor configurado
+	span.SetAttributes(weatherSourceKey.String(o.weather.Source()))
+	temps, err := o.currentWeather(ctx, city)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		return nil, err
 	}
 
 	// Montar resposta final
 	response := &WeatherResponse{
-		City:  city,
-		TempC: temps.TempC,
-		TempF: temps.TempF,
-		TempK: temps.TempK,
+		City:   city,
+		TempC:  temps.TempC,
+		TempF:  temps.TempF,
+		TempK:  temps.TempK,
+		Source: o.weather.Source(),
 	}
 
 	return response, nil
@@ -50,45 +91,100 @@ func (o *WeatherOrchestrator) GetWeatherByCEP(ctx context.Context, cep string) (
 	defer span.End()
 
 	// Buscar cidade no ViaCEP
-	address, err := BuscaViaCepApi(cep)
+	trackCEP := telemetry.TrackTiming(ctx, "cep-lookup")
+	address, err := o.address(ctx, cep)
+	trackCEP()
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordError(span, err)
 		if err.Error() == "can not find zipcode" {
 			return nil, ErrCEPNotFound
 		}
 		return nil, err
 	}
 	if address.City == "" {
-		log.Printf("[DEBUG] Nome da cidade vazio para o CEP: %s", cep)
-		span.RecordError(ErrCEPNotFound)
+		slog.DebugContext(ctx, "Nome da cidade vazio", "cep", cep)
+		telemetry.RecordError(span, ErrCEPNotFound)
 		return nil, ErrCEPNotFound
 	}
-	log.Printf("[DEBUG] Nome da cidade retornado pelo ViaCEP: %s", address.City)
+	slog.DebugContext(ctx, "Nome da cidade retornado pelo ViaCEP", "city", address.City)
 
-	// Buscar dados de clima na WeatherAPI
-	useMock := viper.GetString("WEATHER_API") == ""
-	temps, err := GetWeatherAPICall(address.City)
+	// Buscar dados de clima no provedor configurado
+	span.SetAttributes(weatherSourceKey.String(o.weather.Source()))
+	trackWeather := telemetry.TrackTiming(ctx, "weather-lookup")
+	temps, err := o.currentWeather(ctx, address.City)
+	trackWeather()
 	if err != nil {
-		span.RecordError(err)
-		if useMock {
-			// Se mock está ativo, sempre retorna dados fixos
-			return &WeatherResponse{
-				City:  address.City,
-				TempC: 25.0,
-				TempF: 77.0,
-				TempK: 298.15,
-			}, nil
+		telemetry.RecordError(span, err)
+		if errors.Is(err, ErrCityNotFound) {
+			return nil, ErrCityNotFound
 		}
-		return nil, fmt.Errorf("can not find city")
+		return nil, fmt.Errorf("can not find city: %w", err)
 	}
 
 	// Montar resposta final
 	response := &WeatherResponse{
-		City:  address.City,
-		TempC: temps.TempC,
-		TempF: temps.TempF,
-		TempK: temps.TempK,
+		City:   address.City,
+		TempC:  temps.TempC,
+		TempF:  temps.TempF,
+		TempK:  temps.TempK,
+		Source: o.weather.Source(),
 	}
 
 	return response, nil
 }
+
+// address busca o endereço no ViaCEP respeitando a cota e a parte do prazo da
+// requisição reservada ao CEP
+func (o *WeatherOrchestrator) address(ctx context.Context, cep string) (AddressResponse, error) {
+	timeout, err := deadline.Budget(ctx, o.providers.CEPBudgetShare, o.providers.Timeout, o.providers.MinBudget)
+	if err != nil {
+		return AddressResponse{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if o.providers.Hedger == nil {
+		return o.lookupCEP(ctx, cep, false)
+	}
+	return hedge.Do(ctx, o.providers.Hedger, func(ctx context.Context, hedged bool) (AddressResponse, error) {
+		return o.lookupCEP(ctx, cep, hedged)
+	})
+}
+
+// lookupCEP consulta o ViaCEP dentro da sua cota e do seu limite de chamadas
+// simultâneas; a segunda tentativa vai ao provedor secundário, se houver. A cota é
+// reservada antes da vaga, para que a espera pela cota não ocupe o bulkhead.
+func (o *WeatherOrchestrator) lookupCEP(ctx context.Context, cep string, hedged bool) (AddressResponse, error) {
+	if hedged && o.providers.SecondaryBaseURL != "" {
+		release, err := o.providers.SecondaryBulkhead.Acquire(ctx)
+		if err != nil {
+			return AddressResponse{}, err
+		}
+		address, err := BuscaViaCepApiComURL(ctx, o.providers.SecondaryClient, cep, o.providers.SecondaryBaseURL)
+		release(err)
+		return address, err
+	}
+
+	if err := o.providers.ViaCEPQuota.Acquire(ctx); err != nil {
+		return AddressResponse{}, err
+	}
+	release, err := o.providers.ViaCEPBulkhead.Acquire(ctx)
+	if err != nil {
+		o.providers.ViaCEPQuota.Refund()
+		return AddressResponse{}, err
+	}
+	address, err := BuscaViaCepApiComURL(ctx, o.providers.ViaCEPClient, cep, o.providers.ViaCEPBaseURL)
+	release(err)
+	return address, err
+}
+
+// currentWeather busca o clima no provedor com o restante do prazo da requisição
+func (o *WeatherOrchestrator) currentWeather(ctx context.Context, city string) (ResponseTemps, error) {
+	timeout, err := deadline.Budget(ctx, 1, o.providers.Timeout, o.providers.MinBudget)
+	if err != nil {
+		return ResponseTemps{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return o.weather.Current(ctx, city)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/marfebr/otel-lab/service-b/internal/bulkhead"
+	"github.com/marfebr/otel-lab/service-b/internal/quota"
+)
+
+// Modos do provedor de clima
+const (
+	WeatherModeLive    = "live"
+	WeatherModeMock    = "mock"
+	WeatherModeFixture = "fixture"
+)
+
+// WeatherSourceHeader header de resposta com a origem dos dados de clima
+const WeatherSourceHeader = "X-Weather-Source"
+
+// WeatherProvider fonte dos dados de clima atual de uma cidade
+type WeatherProvider interface {
+	// Source identifica a origem dos dados (live, mock ou fixture)
+	Source() string
+	Current(ctx context.Context, city string) (ResponseTemps, error)
+}
+
+// WeatherProviderConfig configuração da fonte de dados de clima
+type WeatherProviderConfig struct {
+	Mode    string
+	BaseURL string
+	APIKey  string
+	// MockSeed semente dos valores gerados no modo mock
+	MockSeed int64
+	// FixtureFile arquivo JSON com respostas da WeatherAPI por cidade (modo fixture)
+	FixtureFile string
+	// Quota cota de chamadas à WeatherAPI no modo live (nil não limita)
+	Quota *quota.Governor
+	// Client cliente HTTP compartilhado das chamadas à WeatherAPI (nil usa o padrão)
+	Client *http.Client
+	// Bulkhead chamadas simultâneas à WeatherAPI no modo live (nil não limita)
+	Bulkhead *bulkhead.Bulkhead
+}
+
+// NewWeatherProvider cria o provedor do modo configurado
+func NewWeatherProvider(cfg WeatherProviderConfig) (WeatherProvider, error) {
+	switch cfg.Mode {
+	case WeatherModeLive:
+		return &liveWeatherProvider{
+			baseURL:  cfg.BaseURL,
+			apiKey:   cfg.APIKey,
+			quota:    cfg.Quota,
+			client:   cfg.Client,
+			bulkhead: cfg.Bulkhead,
+		}, nil
+	case WeatherModeMock:
+		return &mockWeatherProvider{seed: cfg.MockSeed, converter: NewTemperatureConverter()}, nil
+	case WeatherModeFixture:
+		return newFixtureWeatherProvider(cfg.FixtureFile)
+	default:
+		return nil, fmt.Errorf("unknown weather provider mode: %s", cfg.Mode)
+	}
+}
+
+// liveWeatherProvider consulta a WeatherAPI
+type liveWeatherProvider struct {
+	baseURL  string
+	apiKey   string
+	quota    *quota.Governor
+	client   *http.Client
+	bulkhead *bulkhead.Bulkhead
+}
+
+func (p *liveWeatherProvider) Source() string { return WeatherModeLive }
+
+func (p *liveWeatherProvider) Current(ctx context.Context, city string) (ResponseTemps, error) {
+	// Cota antes da vaga, como no ViaCEP
+	if err := p.quota.Acquire(ctx); err != nil {
+		return ResponseTemps{}, err
+	}
+	release, err := p.bulkhead.Acquire(ctx)
+	if err != nil {
+		p.quota.Refund()
+		return ResponseTemps{}, err
+	}
+	temps, err := GetWeatherAPICallWithURL(ctx, p.client, city, p.baseURL, p.apiKey)
+	release(err)
+	return temps, err
+}
+
+// mockWeatherProvider gera temperaturas determinísticas por cidade a partir da semente
+type mockWeatherProvider struct {
+	seed      int64
+	converter TemperatureConverter
+}
+
+func (p *mockWeatherProvider) Source() string { return WeatherModeMock }
+
+// Current retorna uma temperatura entre -10.0 e 40.0 °C, sempre a mesma para a cidade e a semente
+func (p *mockWeatherProvider) Current(_ context.Context, city string) (ResponseTemps, error) {
+	h := fnv.New64a()
+	binary.Write(h, binary.BigEndian, p.seed)
+	h.Write([]byte(normalizeCity(city)))
+
+	celsius := -10 + float64(h.Sum64()%501)/10
+	tempC, tempF, tempK := p.converter.ConvertFromCelsius(celsius)
+
+	return ResponseTemps{TempC: tempC, TempF: tempF, TempK: tempK}, nil
+}
+
+// fixtureWeatherProvider responde com respostas gravadas da WeatherAPI
+type fixtureWeatherProvider struct {
+	responses map[string]ResponseTemps
+}
+
+// newFixtureWeatherProvider carrega o arquivo no formato {"cidade": <resposta do current.json>}
+func newFixtureWeatherProvider(path string) (*fixtureWeatherProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read weather fixtures: %w", err)
+	}
+
+	var fixtures map[string]WeatherAPIResponse
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse weather fixtures %s: %w", path, err)
+	}
+
+	responses := make(map[string]ResponseTemps, len(fixtures))
+	for city, fixture := range fixtures {
+		responses[normalizeCity(city)] = ResponseTemps{
+			TempC: fixture.Current.TempC,
+			TempF: fixture.Current.TempF,
+			TempK: fixture.Current.TempC + 273.15,
+		}
+	}
+
+	return &fixtureWeatherProvider{responses: responses}, nil
+}
+
+func (p *fixtureWeatherProvider) Source() string { return WeatherModeFixture }
+
+func (p *fixtureWeatherProvider) Current(_ context.Context, city string) (ResponseTemps, error) {
+	temps, ok := p.responses[normalizeCity(city)]
+	if !ok {
+		return ResponseTemps{}, ErrCityNotFound
+	}
+	return temps, nil
+}
+
+func normalizeCity(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
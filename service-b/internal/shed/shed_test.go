@@ -0,0 +1,58 @@
+package shed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdmitShed(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   Limits
+		priority Priority
+		reason   string
+	}{
+		{
+			name:     "queue full",
+			limits:   Limits{MaxInFlight: 1, MaxQueueTime: time.Minute, LowPriorityShare: 1},
+			priority: PriorityNormal,
+			reason:   ReasonQueueFull,
+		},
+		{
+			name:     "queue timeout",
+			limits:   Limits{MaxInFlight: 1, MaxQueue: 1, MaxQueueTime: 10 * time.Millisecond, LowPriorityShare: 1},
+			priority: PriorityNormal,
+			reason:   ReasonQueueTimeout,
+		},
+		{
+			name:     "low priority above its share",
+			limits:   Limits{MaxInFlight: 2, MaxQueue: 1, MaxQueueTime: time.Minute, LowPriorityShare: 0.5},
+			priority: PriorityLow,
+			reason:   ReasonLowPriority,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(tt.limits)
+			release, err := l.Admit(context.Background(), PriorityNormal)
+			if err != nil {
+				t.Fatalf("Admit() error = %v", err)
+			}
+			defer release()
+
+			_, err = l.Admit(context.Background(), tt.priority)
+			var shedErr *ShedError
+			if !errors.As(err, &shedErr) || shedErr.Reason != tt.reason || !errors.Is(err, ErrOverloaded) {
+				t.Fatalf("Admit() error = %v, want ShedError with reason %s", err, tt.reason)
+			}
+
+			// Críticas são admitidas mesmo acima do limite
+			if _, err := l.Admit(context.Background(), PriorityCritical); err != nil {
+				t.Errorf("Admit() of a critical request error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package telemetry
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sensitiveQueryParams parâmetros de query com credenciais (ex.: a chave da
+// WeatherAPI), mascarados nos spans e nas mensagens de erro
+var sensitiveQueryParams = []string{"key"}
+
+// redacted valor exibido no lugar das credenciais
+const redacted = "REDACTED"
+
+// NewTransport instrumenta um RoundTripper com spans de cliente HTTP (semconv),
+// propagando o contexto OTEL nos headers da requisição; as credenciais da query
+// não aparecem em url.full
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(redactingTransport{base: base}, otelhttp.WithSpanNameFormatter(methodSpanName))
+}
+
+// redactingTransport sobrescreve o url.full registrado pelo otelhttp, que só remove
+// o userinfo, pela URL com as credenciais da query mascaradas
+type redactingTransport struct {
+	base http.RoundTripper
+}
+
+func (t redactingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if full := RedactURL(req.URL); full != req.URL.String() {
+		trace.SpanFromContext(req.Context()).SetAttributes(semconv.URLFull(full))
+	}
+	return t.base.RoundTrip(req)
+}
+
+// RedactURL retorna a URL com o userinfo removido e os parâmetros sensíveis da
+// query mascarados
+func RedactURL(u *url.URL) string {
+	redactedURL := *u
+	redactedURL.User = nil
+	query := u.Query()
+	changed := false
+	for _, param := range sensitiveQueryParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		redactedURL.RawQuery = query.Encode()
+	}
+	return redactedURL.String()
+}
+
+// RedactError mascara as credenciais da URL de um *url.Error, cuja mensagem inclui
+// a URL completa da requisição; outros erros são retornados sem alteração
+func RedactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			urlErr.URL = RedactURL(u)
+		}
+	}
+	return err
+}
+
+// NewHandler instrumenta um handler com spans de servidor HTTP (semconv),
+// extraindo o contexto OTEL dos headers da requisição
+func NewHandler(next http.Handler, operation string, opts ...otelhttp.Option) http.Handler {
+	opts = append([]otelhttp.Option{otelhttp.WithSpanNameFormatter(methodSpanName)}, opts...)
+	return otelhttp.NewHandler(next, operation, opts...)
+}
+
+// methodSpanName nomeia o span pelo método HTTP, conforme semconv
+func methodSpanName(_ string, r *http.Request) string {
+	return r.Method
+}
+
+// RecordError registra o erro no span e marca seu status como Error
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,92 @@
+package telemetry
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// idFields campos de ID que o OTLP/JSON representa em hexadecimal (e o protojson em base64)
+var idFields = map[string]bool{
+	"traceId":      true,
+	"spanId":       true,
+	"parentSpanId": true,
+}
+
+// marshalOTLPJSON serializa a mensagem OTLP em uma linha JSON, com IDs em hexadecimal
+// conforme a especificação OTLP/JSON
+func marshalOTLPJSON(msg proto.Message) ([]byte, error) {
+	raw, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return convertIDs(raw, base64ToHex)
+}
+
+// unmarshalOTLPJSON lê uma linha OTLP/JSON (IDs em hexadecimal) para a mensagem
+func unmarshalOTLPJSON(line []byte, msg proto.Message) error {
+	raw, err := convertIDs(line, hexToBase64)
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(raw, msg)
+}
+
+// convertIDs reescreve os campos de ID do documento JSON com a função informada
+func convertIDs(raw []byte, convert func(string) (string, error)) ([]byte, error) {
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	if err := walkIDs(doc, convert); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// walkIDs percorre o documento convertendo os campos de ID encontrados
+func walkIDs(node any, convert func(string) (string, error)) error {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if s, ok := value.(string); ok && idFields[key] {
+				converted, err := convert(s)
+				if err != nil {
+					return fmt.Errorf("invalid %s %q: %w", key, s, err)
+				}
+				v[key] = converted
+				continue
+			}
+			if err := walkIDs(value, convert); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if err := walkIDs(value, convert); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func base64ToHex(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hexToBase64(s string) (string, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewPropagator cria o propagador composto a partir de uma lista separada por vírgulas
+// no formato de OTEL_PROPAGATORS (tracecontext, baggage, b3, b3multi, none); none
+// desativa a propagação e não pode ser combinado com outros
+func NewPropagator(names string) (propagation.TextMapPropagator, error) {
+	var propagators []propagation.TextMapPropagator
+
+	list := SplitList(names)
+	for _, name := range list {
+		switch strings.ToLower(name) {
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+		case "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case "b3multi":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case "none":
+			if len(list) > 1 {
+				return nil, fmt.Errorf("propagator %q cannot be combined with other propagators", name)
+			}
+			return propagation.NewCompositeTextMapPropagator(), nil
+		default:
+			return nil, fmt.Errorf("unknown propagator %q", name)
+		}
+	}
+
+	if len(propagators) == 0 {
+		propagators = append(propagators, propagation.TraceContext{}, propagation.Baggage{})
+	}
+
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
+
+// SplitList separa uma lista de valores por vírgula, ignorando itens vazios
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// BaggageSpanProcessor copia membros selecionados do baggage para atributos do span
+type BaggageSpanProcessor struct {
+	keys []string
+}
+
+// NewBaggageSpanProcessor cria um processor que promove as chaves de baggage informadas
+func NewBaggageSpanProcessor(keys []string) *BaggageSpanProcessor {
+	return &BaggageSpanProcessor{keys: keys}
+}
+
+// OnStart adiciona ao span os membros de baggage presentes no contexto pai
+func (p *BaggageSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	bag := baggage.FromContext(parent)
+	for _, key := range p.keys {
+		if member := bag.Member(key); member.Key() != "" {
+			s.SetAttributes(attribute.String(key, member.Value()))
+		}
+	}
+}
+
+// OnEnd não faz nada
+func (p *BaggageSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+// Shutdown não faz nada
+func (p *BaggageSpanProcessor) Shutdown(context.Context) error { return nil }
+
+// ForceFlush não faz nada
+func (p *BaggageSpanProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+// maxReplayLine tamanho máximo de uma linha OTLP/JSON lida no replay
+const maxReplayLine = 64 * 1024 * 1024
+
+// ReplayStats totais enviados ao collector pelo replay
+type ReplayStats struct {
+	TraceBatches  int
+	MetricBatches int
+	Skipped       int
+}
+
+// Replay reenvia ao collector (OTLP gRPC) os arquivos gravados pelo exportador "file".
+// Cada linha é um ExportTraceServiceRequest ou ExportMetricsServiceRequest em OTLP/JSON.
+func Replay(ctx context.Context, endpoint string, tlsCfg OTLPTLSConfig, paths []string) (ReplayStats, error) {
+	var stats ReplayStats
+
+	conn, err := newCollectorConn(endpoint, tlsCfg)
+	if err != nil {
+		return stats, err
+	}
+	defer conn.Close()
+
+	traces := coltracepb.NewTraceServiceClient(conn)
+	metrics := colmetricpb.NewMetricsServiceClient(conn)
+
+	for _, path := range paths {
+		if err := replayFile(ctx, path, traces, metrics, &stats); err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// replayFile envia as linhas de um arquivo
+func replayFile(ctx context.Context, path string, traces coltracepb.TraceServiceClient, metrics colmetricpb.MetricsServiceClient, stats *ReplayStats) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 1024*1024), maxReplayLine)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var kind map[string]json.RawMessage
+		if err := json.Unmarshal(line, &kind); err != nil {
+			return fmt.Errorf("%s:%d: invalid JSON: %w", path, lineNumber, err)
+		}
+
+		switch {
+		case kind["resourceSpans"] != nil:
+			var req coltracepb.ExportTraceServiceRequest
+			if err := unmarshalOTLPJSON(line, &req); err != nil {
+				return fmt.Errorf("%s:%d: invalid trace request: %w", path, lineNumber, err)
+			}
+			if _, err := traces.Export(ctx, &req); err != nil {
+				return fmt.Errorf("%s:%d: failed to export traces: %w", path, lineNumber, err)
+			}
+			stats.TraceBatches++
+		case kind["resourceMetrics"] != nil:
+			var req colmetricpb.ExportMetricsServiceRequest
+			if err := unmarshalOTLPJSON(line, &req); err != nil {
+				return fmt.Errorf("%s:%d: invalid metrics request: %w", path, lineNumber, err)
+			}
+			if _, err := metrics.Export(ctx, &req); err != nil {
+				return fmt.Errorf("%s:%d: failed to export metrics: %w", path, lineNumber, err)
+			}
+			stats.MetricBatches++
+		default:
+			stats.Skipped++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
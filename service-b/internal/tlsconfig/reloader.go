@@ -0,0 +1,109 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// checkInterval intervalo mínimo entre verificações dos arquivos do certificado
+const checkInterval = time.Second
+
+// certificateReloads recargas de certificado por resultado
+var certificateReloads = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "tls_certificate_reloads_total",
+	Help: "TLS certificate reloads after the files changed, by result.",
+}, []string{"result"})
+
+// CertReloader mantém o par certificado/chave em memória e o relê quando a data de
+// modificação dos arquivos muda. A verificação acontece durante os handshakes, no
+// máximo uma vez por segundo; se a leitura falhar (ex.: arquivos pela metade), o
+// certificado anterior continua em uso.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+// NewCertReloader carrega o certificado inicial
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+
+	modTime, err := r.statFiles()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate %s: %w", certFile, err)
+	}
+
+	r.cert = &cert
+	r.modTime = modTime
+	r.checked = time.Now()
+	return r, nil
+}
+
+// GetCertificate implementa tls.Config.GetCertificate
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
+
+// GetClientCertificate implementa tls.Config.GetClientCertificate
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
+
+// current retorna o certificado, relendo os arquivos se tiverem mudado
+func (r *CertReloader) current() *tls.Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) < checkInterval {
+		return r.cert
+	}
+	r.checked = time.Now()
+
+	modTime, err := r.statFiles()
+	if err != nil || modTime.Equal(r.modTime) {
+		return r.cert
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		certificateReloads.WithLabelValues("error").Inc()
+		slog.Warn("TLS certificate reload failed, keeping the previous one", "cert", r.certFile, "error", err)
+		return r.cert
+	}
+
+	r.cert = &cert
+	r.modTime = modTime
+	certificateReloads.WithLabelValues("success").Inc()
+	slog.Info("TLS certificate reloaded", "cert", r.certFile)
+	return r.cert
+}
+
+// statFiles retorna a modificação mais recente entre o certificado e a chave
+func (r *CertReloader) statFiles() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read TLS certificate: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
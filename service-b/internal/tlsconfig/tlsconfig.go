@@ -0,0 +1,85 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// ParseVersion converte a versão mínima ("1.2" ou "1.3") para a constante do crypto/tls
+func ParseVersion(version string) (uint16, error) {
+	switch version {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q (expected 1.2 or 1.3)", version)
+	}
+}
+
+// ServerConfig configuração TLS de um listener; o certificado é recarregado quando
+// os arquivos mudam, sem reiniciar o processo
+func ServerConfig(certFile, keyFile string, minVersion uint16) (*tls.Config, error) {
+	reloader, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     minVersion,
+	}, nil
+}
+
+// ClientOptions opções TLS de uma conexão de saída
+type ClientOptions struct {
+	// CAFile CAs aceitas para o servidor (vazio usa as CAs do sistema)
+	CAFile string
+	// CertFile e KeyFile certificado de cliente (opcional)
+	CertFile string
+	KeyFile  string
+	// ServerName nome esperado no certificado do servidor (vazio usa o host do endereço)
+	ServerName string
+	MinVersion uint16
+}
+
+// ClientConfig configuração TLS de uma conexão de saída; o certificado de cliente
+// também é recarregado quando os arquivos mudam
+func ClientConfig(opts ClientOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName: opts.ServerName,
+		MinVersion: opts.MinVersion,
+	}
+
+	if opts.CAFile != "" {
+		pool, err := LoadCAPool(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.CertFile != "" {
+		reloader, err := NewCertReloader(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GetClientCertificate = reloader.GetClientCertificate
+	}
+
+	return cfg, nil
+}
+
+// LoadCAPool lê um bundle PEM de CAs
+func LoadCAPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,4 @@
+// Package weatherpb tipos e stubs gRPC gerados a partir de proto/weather/v1/weather.proto
+package weatherpb
+
+//go:generate protoc -I ../../../proto --go_out=. --go_opt=module=github.com/marfebr/otel-lab/service-b/internal/weatherpb,Mweather/v1/weather.proto=github.com/marfebr/otel-lab/service-b/internal/weatherpb --go-grpc_out=. --go-grpc_opt=module=github.com/marfebr/otel-lab/service-b/internal/weatherpb,Mweather/v1/weather.proto=github.com/marfebr/otel-lab/service-b/internal/weatherpb weather/v1/weather.proto
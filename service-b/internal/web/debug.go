@@ -0,0 +1,129 @@
+package web
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// debugTracesTemplate página de resumo dos spans (estilo zpages)
+var debugTracesTemplate = template.Must(template.New("traces").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Service}} - traces</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.error { color: #b00; }
+</style>
+</head>
+<body>
+<h1>{{.Service}} - spans recentes</h1>
+<table>
+<tr>
+<th>Span</th><th>Em andamento</th><th>Total</th><th>Erros</th>
+{{range .Buckets}}<th>{{.}}</th>{{end}}
+</tr>
+{{range .Summaries}}
+<tr>
+<td><a href="?name={{.Name}}">{{.Name}}</a></td>
+<td>{{.Active}}</td>
+<td>{{.Total}}</td>
+<td{{if .Errors}} class="error"{{end}}>{{.Errors}}</td>
+{{range .Latency}}<td>{{.}}</td>{{end}}
+</tr>
+{{end}}
+</table>
+{{if .Name}}
+<h2>{{.Name}}</h2>
+<table>
+<tr><th>Trace</th><th>Início</th><th>Duração (ms)</th><th>Status</th><th>Em andamento</th></tr>
+{{range .Spans}}
+<tr>
+<td><a href="traces/{{.TraceID}}">{{.TraceID}}</a></td>
+<td>{{.StartTime.Format "15:04:05.000"}}</td>
+<td>{{printf "%.3f" .DurationMs}}</td>
+<td{{if eq .Status "Error"}} class="error"{{end}}>{{.Status}} {{.StatusMessage}}</td>
+<td>{{.InFlight}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+</body>
+</html>
+`))
+
+// debugHandler páginas de inspeção dos spans mantidos em memória
+type debugHandler struct {
+	service string
+	store   *telemetry.SpanStore
+}
+
+// handleTraces renderiza o resumo por nome de span e, com ?name=, os spans desse nome
+func (h *debugHandler) handleTraces(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	data := struct {
+		Service   string
+		Buckets   []string
+		Summaries []telemetry.SpanSummary
+		Name      string
+		Spans     []telemetry.SpanView
+	}{
+		Service:   h.service,
+		Buckets:   latencyBucketLabels(),
+		Summaries: h.store.Summaries(),
+		Name:      name,
+	}
+	if name != "" {
+		data.Spans = h.store.SpansByName(name)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := debugTracesTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleTrace retorna em JSON a árvore local de spans do trace
+func (h *debugHandler) handleTrace(w http.ResponseWriter, r *http.Request) {
+	traceID, err := trace.TraceIDFromHex(chi.URLParam(r, "traceID"))
+	if err != nil {
+		http.Error(w, "invalid trace id", http.StatusBadRequest)
+		return
+	}
+
+	spans := h.store.Trace(traceID)
+	if len(spans) == 0 {
+		http.Error(w, "trace not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		TraceID string                `json:"trace_id"`
+		Service string                `json:"service"`
+		Spans   []*telemetry.SpanView `json:"spans"`
+	}{
+		TraceID: traceID.String(),
+		Service: h.service,
+		Spans:   spans,
+	})
+}
+
+// latencyBucketLabels rótulos das colunas de latência
+func latencyBucketLabels() []string {
+	labels := make([]string, 0, len(telemetry.LatencyBounds)+1)
+	for _, bound := range telemetry.LatencyBounds {
+		labels = append(labels, "<"+bound.String())
+	}
+	last := telemetry.LatencyBounds[len(telemetry.LatencyBounds)-1]
+	return append(labels, ">="+last.String())
+}
@@ -0,0 +1,65 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestDuration histograma de latência das requisições HTTP do servidor
+var requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "http_server_request_duration_seconds",
+	Help:    "Duration of HTTP server requests.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "route", "status_code"})
+
+// metricsHandler expõe as métricas no formato OpenMetrics (necessário para exemplars)
+func metricsHandler() http.Handler {
+	return promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})
+}
+
+// metricsMiddleware mede a duração de cada requisição, anexando o trace ID do span
+// de servidor como exemplar quando o span é amostrado
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := "other"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		observer := requestDuration.WithLabelValues(r.Method, route, strconv.Itoa(status))
+		observeWithTrace(observer, time.Since(start).Seconds(), trace.SpanContextFromContext(r.Context()))
+	})
+}
+
+// observeWithTrace registra a observação com exemplar trace_id/span_id se o span for amostrado
+func observeWithTrace(observer prometheus.Observer, value float64, sc trace.SpanContext) {
+	exemplarObserver, ok := observer.(prometheus.ExemplarObserver)
+	if !ok || !sc.IsSampled() {
+		observer.Observe(value)
+		return
+	}
+
+	exemplarObserver.ObserveWithExemplar(value, prometheus.Labels{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+	})
+}
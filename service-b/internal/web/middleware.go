@@ -0,0 +1,66 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marfebr/otel-lab/service-b/internal/shed"
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// untracedPaths endpoints operacionais que não geram spans
+var untracedPaths = map[string]bool{
+	"/metrics": true,
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// tracingMiddleware cria o span de servidor de cada requisição, ignorando os
+// endpoints operacionais e /debug
+func tracingMiddleware(operation string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return telemetry.NewHandler(routeTagger(next), operation,
+			otelhttp.WithFilter(func(r *http.Request) bool {
+				return !untracedPaths[r.URL.Path] && !strings.HasPrefix(r.URL.Path, "/debug/")
+			}),
+		)
+	}
+}
+
+// routeTagger adiciona http.route ao span de servidor e o renomeia para "MÉTODO rota"
+// após o roteamento do chi
+func routeTagger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		rctx := chi.RouteContext(r.Context())
+		if rctx == nil {
+			return
+		}
+		route := rctx.RoutePattern()
+		if route == "" {
+			return
+		}
+
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	})
+}
+
+// priority classe de admissão da requisição: os endpoints operacionais sempre entram
+// e /debug e /admin só usam a folga do servidor
+func priority(r *http.Request) shed.Priority {
+	switch {
+	case untracedPaths[r.URL.Path]:
+		return shed.PriorityCritical
+	case strings.HasPrefix(r.URL.Path, "/debug/"), strings.HasPrefix(r.URL.Path, "/admin/"):
+		return shed.PriorityLow
+	default:
+		return shed.PriorityNormal
+	}
+}
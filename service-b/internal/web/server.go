@@ -1,13 +1,26 @@
 package web
 
 import (
+	"context"
+	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
+	"github.com/marfebr/otel-lab/service-b/internal/bulkhead"
+	"github.com/marfebr/otel-lab/service-b/internal/config"
+	"github.com/marfebr/otel-lab/service-b/internal/deadline"
+	"github.com/marfebr/otel-lab/service-b/internal/fault"
 	"github.com/marfebr/otel-lab/service-b/internal/handler"
+	"github.com/marfebr/otel-lab/service-b/internal/health"
+	"github.com/marfebr/otel-lab/service-b/internal/hedge"
+	"github.com/marfebr/otel-lab/service-b/internal/httpclient"
+	"github.com/marfebr/otel-lab/service-b/internal/peerauth"
+	"github.com/marfebr/otel-lab/service-b/internal/quota"
 	"github.com/marfebr/otel-lab/service-b/internal/service"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/marfebr/otel-lab/service-b/internal/shed"
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
+	"github.com/marfebr/otel-lab/service-b/internal/tlsconfig"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -15,15 +28,108 @@ import (
 type Server struct {
 	router         *chi.Mux
 	weatherHandler *handler.WeatherHandler
+	orchestrator   *service.WeatherOrchestrator
+	checker        *health.Checker
+	credentials    *service.WeatherAPICredentials
+	viaCEPQuota    *quota.Governor
+	weatherQuota   *quota.Governor
+	admission      *shed.Limiter
+	faults         *fault.Injector
 	tracer         trace.Tracer
 }
 
 // NewServer cria uma nova instância do servidor
 func NewServer(
 	tracer trace.Tracer,
-) *Server {
+	cfg *config.Config,
+	spanStore *telemetry.SpanStore,
+) (*Server, error) {
+	// Criar cotas de chamadas de saída por provedor
+	viaCEPQuota := quota.NewGovernor("viacep", cfg.Quotas.ViaCEP)
+	weatherQuota := quota.NewGovernor("weatherapi", cfg.Quotas.WeatherAPI)
+
+	// Criar injeção de falhas (apenas para testes)
+	var faults *fault.Injector
+	if cfg.Fault.Enabled {
+		faults = fault.NewInjector(cfg.Fault.Rules)
+	}
+
+	// Criar um transporte compartilhado por provedor, reaproveitando as conexões
+	outboundTLS, err := tlsconfig.ClientConfig(tlsconfig.ClientOptions{MinVersion: cfg.TLS.MinVersion})
+	if err != nil {
+		return nil, err
+	}
+	viaCEPTransport := httpclient.NewTransport("viacep", cfg.HTTPClient, outboundTLS)
+	weatherTransport := httpclient.NewTransport("weatherapi", cfg.HTTPClient, outboundTLS)
+
+	// Criar provedor de clima do modo configurado
+	weather, err := service.NewWeatherProvider(service.WeatherProviderConfig{
+		Mode:        cfg.Weather.Mode,
+		BaseURL:     cfg.Weather.BaseURL,
+		APIKey:      cfg.Weather.APIKey,
+		MockSeed:    cfg.Weather.MockSeed,
+		FixtureFile: cfg.Weather.FixtureFile,
+		Quota:       weatherQuota,
+		Client:      &http.Client{Transport: telemetry.NewTransport(faults.Transport("weatherapi", weatherTransport))},
+		Bulkhead:    bulkhead.New("weatherapi", cfg.Bulkheads.WeatherAPI),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Configurar o ViaCEP e, se ativa, a segunda tentativa (hedge) da busca de CEP
+	providers := service.ProviderConfig{
+		ViaCEPBaseURL:  cfg.ViaCEPBaseURL,
+		ViaCEPQuota:    viaCEPQuota,
+		ViaCEPBulkhead: bulkhead.New("viacep", cfg.Bulkheads.ViaCEP),
+		ViaCEPClient:   &http.Client{Transport: telemetry.NewTransport(faults.Transport("viacep", viaCEPTransport))},
+		Timeout:        cfg.UpstreamTimeout,
+		CEPBudgetShare: cfg.CEPBudgetShare,
+		MinBudget:      cfg.MinUpstreamBudget,
+	}
+	if cfg.Hedge.Enabled {
+		providers.Hedger = hedge.NewHedger("viacep", cfg.Hedge.Policy, tracer)
+		if cfg.Hedge.SecondaryURL != "" {
+			secondaryTransport := httpclient.NewTransport("viacep-secondary", cfg.HTTPClient, outboundTLS)
+			providers.SecondaryBaseURL = cfg.Hedge.SecondaryURL
+			providers.SecondaryClient = &http.Client{Transport: telemetry.NewTransport(faults.Transport("viacep-secondary", secondaryTransport))}
+			providers.SecondaryBulkhead = bulkhead.New("viacep-secondary", cfg.Bulkheads.ViaCEP)
+		}
+	}
+
 	// Criar handler de clima
-	weatherHandler := handler.NewWeatherHandler(service.NewWeatherOrchestrator(tracer), tracer)
+	orchestrator := service.NewWeatherOrchestrator(tracer, weather, providers)
+	weatherHandler := handler.NewWeatherHandler(orchestrator, tracer)
+
+	// Criar verificação de prontidão (provedor de clima e ViaCEP): o alcance é testado
+	// abrindo uma conexão e a chave da WeatherAPI com uma chamada leve que passa pela
+	// cota e fica em cache por HEALTH_CREDENTIALS_TTL; sem instrumentação, mas no mesmo
+	// pool das chamadas
+	credentials := service.NewWeatherAPICredentials(&http.Client{Timeout: 5 * time.Second, Transport: weatherTransport},
+		cfg.Weather.BaseURL, cfg.Weather.APIKey, weatherQuota, cfg.HealthCredentialsTTL)
+	checker := health.NewChecker(cfg.HealthCacheTTL, 3*time.Second,
+		health.Check{
+			Name: "weatherapi",
+			Run: func(ctx context.Context) (string, error) {
+				if weather.Source() != service.WeatherModeLive {
+					return weather.Source() + " mode", nil
+				}
+				if detail, err := service.CheckReachable(ctx, cfg.Weather.BaseURL, outboundTLS); err != nil {
+					return detail, err
+				}
+				return cfg.Weather.BaseURL, credentials.Check(ctx)
+			},
+		},
+		health.Check{
+			Name: "viacep",
+			Run: func(ctx context.Context) (string, error) {
+				return service.CheckReachable(ctx, cfg.ViaCEPBaseURL, outboundTLS)
+			},
+		},
+	)
+
+	// Criar controle de admissão, compartilhado com o servidor gRPC
+	admission := shed.NewLimiter(cfg.Shed)
 
 	// Criar router
 	router := chi.NewRouter()
@@ -31,19 +137,68 @@ func NewServer(
 	// Configurar middleware
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
+	router.Use(tracingMiddleware("service-b"))
+	router.Use(metricsMiddleware)
+	router.Use(serverTimingMiddleware)
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Logger)
-	router.Use(middleware.Timeout(60 * time.Second))
+	router.Use(admission.Middleware(priority))
+	router.Use(deadline.Middleware(cfg.RequestTimeout))
+	router.Use(faults.Middleware)
 
 	// Configurar endpoints
-	router.Handle("/metrics", promhttp.Handler())
-	router.Post("/weather", weatherHandler.HandleWeatherRequest)
+	router.Handle("/metrics", metricsHandler())
+	router.Get("/healthz", checker.HandleLiveness)
+	router.Get("/readyz", checker.HandleReadiness)
+	if spanStore != nil {
+		debug := &debugHandler{service: "service-b", store: spanStore}
+		router.Get("/debug/traces", debug.handleTraces)
+		router.Get("/debug/traces/{traceID}", debug.handleTrace)
+	}
+	router.With(peerauth.NewVerifier(cfg.ServiceAuth).Middleware).Post("/weather", weatherHandler.HandleWeatherRequest)
 
 	return &Server{
 		router:         router,
 		weatherHandler: weatherHandler,
+		orchestrator:   orchestrator,
+		checker:        checker,
+		credentials:    credentials,
+		viaCEPQuota:    viaCEPQuota,
+		weatherQuota:   weatherQuota,
+		admission:      admission,
+		faults:         faults,
 		tracer:         tracer,
-	}
+	}, nil
+}
+
+// ApplyConfig aplica as configurações recarregadas em execução
+func (s *Server) ApplyConfig(cfg *config.Config) {
+	s.checker.SetTTL(cfg.HealthCacheTTL)
+	s.credentials.SetTTL(cfg.HealthCredentialsTTL)
+	s.viaCEPQuota.SetLimits(cfg.Quotas.ViaCEP)
+	s.weatherQuota.SetLimits(cfg.Quotas.WeatherAPI)
+	s.faults.SetRules(cfg.Fault.Rules)
+}
+
+// Orchestrator retorna o orquestrador compartilhado com a API gRPC
+func (s *Server) Orchestrator() *service.WeatherOrchestrator {
+	return s.orchestrator
+}
+
+// Admission retorna o controle de admissão compartilhado com a API gRPC
+func (s *Server) Admission() *shed.Limiter {
+	return s.admission
+}
+
+// Faults retorna a injeção de falhas compartilhada com a API gRPC (nil se desativada)
+func (s *Server) Faults() *fault.Injector {
+	return s.faults
+}
+
+// Ready informa se o serviço está pronto, com as mesmas verificações (e o mesmo
+// cache) do /readyz; usado pelo health check gRPC
+func (s *Server) Ready(ctx context.Context) bool {
+	return s.checker.Report(ctx).Status == health.StatusOK
 }
 
 // GetRouter retorna o router configurado
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/marfebr/otel-lab/service-b/internal/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader header de resposta com o trace ID da requisição
+const TraceIDHeader = "X-Trace-Id"
+
+// serverTimingMiddleware disponibiliza um TimingRecorder para a requisição e, antes
+// de enviar os headers da resposta, adiciona Server-Timing (com o total), traceparent
+// e X-Trace-Id
+func serverTimingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx, recorder := telemetry.ContextWithTimingRecorder(r.Context())
+		sc := trace.SpanContextFromContext(ctx)
+
+		hw := &headerHookWriter{ResponseWriter: w, hook: func(h http.Header) {
+			entries := append(recorder.Entries(), telemetry.TimingEntry{Name: "total", Duration: time.Since(start)})
+			h.Set(telemetry.ServerTimingHeader, telemetry.FormatServerTiming(entries))
+
+			if sc.IsValid() {
+				h.Set("traceparent", fmt.Sprintf("00-%s-%s-%s", sc.TraceID(), sc.SpanID(), sc.TraceFlags()))
+				h.Set(TraceIDHeader, sc.TraceID().String())
+			}
+		}}
+
+		next.ServeHTTP(hw, r.WithContext(ctx))
+	})
+}
+
+// headerHookWriter executa o hook uma única vez, imediatamente antes do envio dos headers
+type headerHookWriter struct {
+	http.ResponseWriter
+	hook        func(http.Header)
+	wroteHeader bool
+}
+
+func (w *headerHookWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.hook(w.Header())
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *headerHookWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap permite ao http.ResponseController acessar o writer original
+func (w *headerHookWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
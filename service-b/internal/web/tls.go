@@ -0,0 +1,35 @@
+package web
+
+import (
+	"crypto/tls"
+
+	"github.com/marfebr/otel-lab/service-b/internal/config"
+	"github.com/marfebr/otel-lab/service-b/internal/peerauth"
+	"github.com/marfebr/otel-lab/service-b/internal/tlsconfig"
+)
+
+// ListenerTLSConfig configuração TLS do listener HTTP; nil quando nenhum certificado
+// foi configurado (HTTP puro). No modo mtls o listener sempre usa TLS, com o
+// certificado de SERVICE_AUTH_CERT_FILE se HTTP_TLS_CERT_FILE estiver vazio, e
+// verifica o certificado do Serviço A.
+func ListenerTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	certFile, keyFile := cfg.TLS.CertFile, cfg.TLS.KeyFile
+	mtls := cfg.ServiceAuth.Mode == peerauth.ModeMTLS
+	if mtls && certFile == "" {
+		certFile, keyFile = cfg.ServiceAuth.CertFile, cfg.ServiceAuth.KeyFile
+	}
+	if certFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig, err := tlsconfig.ServerConfig(certFile, keyFile, cfg.TLS.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	if mtls {
+		if err := peerauth.VerifyClients(tlsConfig, cfg.ServiceAuth); err != nil {
+			return nil, err
+		}
+	}
+	return tlsConfig, nil
+}